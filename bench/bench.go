@@ -0,0 +1,130 @@
+// Package bench measures the throughput and output quality of random number
+// generators, so any io.Reader or uint64 source can be compared with R30R2
+// using the same methodology as the r30r2 benchmark and compare subcommands.
+package bench
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/vrypan/r30r2/stats"
+)
+
+// BenchResult holds benchmark results
+// Entropy and ChiSquare cover every byte produced. The latency figures are
+// only set by RunUint64Benchmark, per call and derived from per-batch timings.
+type BenchResult struct {
+	Name       string        `json:"name"`
+	Size       int           `json:"size"`
+	Duration   time.Duration `json:"duration"`            // all iterations, in nanoseconds
+	Throughput float64       `json:"throughput"`          // MB/s
+	Entropy    float64       `json:"entropy"`             // bits per byte
+	ChiSquare  float64       `json:"chiSquare"`           // against uniform bytes
+	NsPerCall  float64       `json:"nsPerCall,omitempty"` // mean
+	StdDev     float64       `json:"stdDev,omitempty"`    // standard deviation across batches
+	P50        float64       `json:"p50,omitempty"`       // median batch
+	P99        float64       `json:"p99,omitempty"`       // 99th percentile batch
+	Err        error         `json:"-"`                   // why the run stopped early, if it did
+}
+
+// RunReadBenchmark reads iters buffers of size bytes from r
+// Only the reads are timed. If r fails, the run stops and the result covers
+// the buffers read so far, with the error in Err.
+func RunReadBenchmark(name string, r io.Reader, size, iters int) BenchResult {
+	buf := make([]byte, size)
+	entropy := stats.NewEntropyAccumulator()
+	chi := stats.NewChiSquareAccumulator()
+
+	res := BenchResult{Name: name, Size: size}
+	done := 0
+	for ; done < iters; done++ {
+		start := time.Now()
+		_, err := io.ReadFull(r, buf)
+		res.Duration += time.Since(start)
+		if err != nil {
+			res.Err = err
+			break
+		}
+		entropy.Write(buf)
+		chi.Write(buf)
+	}
+
+	res.Throughput = throughput(size*done, res.Duration)
+	res.Entropy = entropy.Entropy()
+	res.ChiSquare = chi.ChiSquare()
+	return res
+}
+
+// Uint64BatchSize is how many gen calls RunUint64Benchmark times together
+// Timing single calls would mostly measure the clock.
+const Uint64BatchSize = 1024
+
+// RunUint64Benchmark calls gen iters times
+// Calls are timed in batches; the values are analysed as little-endian bytes
+// between batches, and Size is the 8 bytes of one value.
+func RunUint64Benchmark(name string, iters int, gen func() uint64) BenchResult {
+	var vals [Uint64BatchSize]uint64
+	var buf [8 * Uint64BatchSize]byte
+	entropy := stats.NewEntropyAccumulator()
+	chi := stats.NewChiSquareAccumulator()
+	samples := make([]float64, 0, (iters+Uint64BatchSize-1)/Uint64BatchSize)
+
+	res := BenchResult{Name: name, Size: 8}
+	for left := iters; left > 0; {
+		n := min(left, Uint64BatchSize)
+		start := time.Now()
+		for i := range n {
+			vals[i] = gen()
+		}
+		elapsed := time.Since(start)
+		res.Duration += elapsed
+		samples = append(samples, float64(elapsed.Nanoseconds())/float64(n))
+		left -= n
+
+		for i, v := range vals[:n] {
+			binary.LittleEndian.PutUint64(buf[8*i:], v)
+		}
+		entropy.Write(buf[:8*n])
+		chi.Write(buf[:8*n])
+	}
+
+	res.Throughput = throughput(8*iters, res.Duration)
+	res.Entropy = entropy.Entropy()
+	res.ChiSquare = chi.ChiSquare()
+	res.NsPerCall, res.StdDev, res.P50, res.P99 = latencyStats(samples)
+	return res
+}
+
+// latencyStats returns the mean, standard deviation and 50th and 99th
+// percentiles (nearest rank) of samples
+func latencyStats(samples []float64) (mean, stddev, p50, p99 float64) {
+	if len(samples) == 0 {
+		return 0, 0, 0, 0
+	}
+	for _, s := range samples {
+		mean += s
+	}
+	mean /= float64(len(samples))
+	for _, s := range samples {
+		stddev += (s - mean) * (s - mean)
+	}
+	stddev = math.Sqrt(stddev / float64(len(samples)))
+
+	sorted := slices.Sorted(slices.Values(samples))
+	rank := func(p float64) float64 {
+		i := int(math.Ceil(p*float64(len(sorted)))) - 1
+		return sorted[max(i, 0)]
+	}
+	return mean, stddev, rank(0.50), rank(0.99)
+}
+
+// throughput returns n bytes over d in MB/s, or 0 if no time elapsed
+func throughput(n int, d time.Duration) float64 {
+	if d <= 0 {
+		return 0
+	}
+	return float64(n) / d.Seconds() / 1024 / 1024
+}
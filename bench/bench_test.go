@@ -0,0 +1,85 @@
+package bench
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/vrypan/r30r2/rand"
+	"github.com/vrypan/r30r2/stats"
+)
+
+// zeroReader produces an endless stream of zero bytes
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+func TestRunReadBenchmarkZeros(t *testing.T) {
+	res := RunReadBenchmark("zeros", zeroReader{}, 1000, 5)
+	if res.Err != nil || res.Entropy != 0 || res.ChiSquare != 255*5000 {
+		t.Errorf("zero reader: %+v", res)
+	}
+	if res.Name != "zeros" || res.Size != 1000 || res.Duration <= 0 {
+		t.Errorf("zero reader: bad bookkeeping %+v", res)
+	}
+}
+
+func TestRunReadBenchmarkKnownReader(t *testing.T) {
+	res := RunReadBenchmark("R30R2", rand.New(8), 4096, 16)
+	data := rand.New(8).Bytes(4096 * 16)
+	if res.Entropy != stats.ShannonEntropy(data) || res.ChiSquare != stats.ChiSquareUniform(data) {
+		t.Errorf("statistics %v / %v do not cover all %d bytes read", res.Entropy, res.ChiSquare, len(data))
+	}
+	if res.Throughput <= 0 {
+		t.Errorf("throughput = %v", res.Throughput)
+	}
+}
+
+func TestRunReadBenchmarkError(t *testing.T) {
+	res := RunReadBenchmark("short", io.LimitReader(rand.New(1), 2500), 1000, 10)
+	if !errors.Is(res.Err, io.ErrUnexpectedEOF) {
+		t.Errorf("Err = %v, want io.ErrUnexpectedEOF", res.Err)
+	}
+	if data := rand.New(1).Bytes(2000); res.Entropy != stats.ShannonEntropy(data) {
+		t.Error("statistics do not cover exactly the complete buffers")
+	}
+}
+
+func TestRunUint64Benchmark(t *testing.T) {
+	if res := RunUint64Benchmark("zero", 3000, func() uint64 { return 0 }); res.Entropy != 0 || res.Size != 8 {
+		t.Errorf("constant source: %+v", res)
+	}
+
+	const iters = 2500 // not a whole number of batches
+	res := RunUint64Benchmark("R30R2", iters, rand.New(4).Uint64)
+	src := rand.New(4)
+	data := make([]byte, 8*iters)
+	for i := range iters {
+		binary.LittleEndian.PutUint64(data[8*i:], src.Uint64())
+	}
+	if res.Entropy != stats.ShannonEntropy(data) || res.ChiSquare != stats.ChiSquareUniform(data) {
+		t.Errorf("statistics do not cover the %d values generated", iters)
+	}
+	if res.NsPerCall <= 0 || res.StdDev < 0 || res.P50 <= 0 || res.P99 < res.P50 {
+		t.Errorf("implausible latency %+v", res)
+	}
+}
+
+func TestLatencyStats(t *testing.T) {
+	samples := []float64{4, 2, 8, 6, 10, 100}
+	mean, stddev, p50, p99 := latencyStats(samples)
+	if mean != 130.0/6 {
+		t.Errorf("mean = %v, want %v", mean, 130.0/6)
+	}
+	if want := 35.1268; math.Abs(stddev-want) > 1e-4 {
+		t.Errorf("stddev = %v, want about %v", stddev, want)
+	}
+	if p50 != 6 || p99 != 100 {
+		t.Errorf("p50, p99 = %v, %v; want 6, 100", p50, p99)
+	}
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/vrypan/r30r2/rand"
+	"github.com/vrypan/r30r2/stats"
+)
+
+func TestChiSquareUpperTail(t *testing.T) {
+	// The median of chi-square with 255 degrees of freedom is about 254.33
+	if p := chiSquareUpperTail(254.33, 255); math.Abs(p-0.5) > 0.005 {
+		t.Errorf("upper tail at the median = %f, want 0.5", p)
+	}
+	if p := chiSquareUpperTail(400, 255); p > 1e-6 {
+		t.Errorf("upper tail at 400 = %g, want tiny", p)
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	data := make([]byte, 1<<16)
+	rand.New(1).Read(data)
+	a := newAnalyzer()
+	a.Write(data)
+	var buf bytes.Buffer
+	a.writeReport(&buf)
+	for _, want := range []string{"Entropy =", "Chi square distribution for 65536 samples", "Arithmetic mean", "Monte Carlo value for Pi", "Serial correlation"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("report has no %q", want)
+		}
+	}
+}
+
+func TestAnalyzerMatchesBatch(t *testing.T) {
+	data := make([]byte, 100003)
+	rand.New(2).Read(data)
+
+	a := newAnalyzer()
+	for off := 0; off < len(data); off += 4097 {
+		a.Write(data[off:min(off+4097, len(data))])
+	}
+	if got, want := a.pi(), stats.MonteCarloPi(data); got != want {
+		t.Errorf("pi = %v, batch = %v", got, want)
+	}
+	if got, want := a.serial(), stats.SerialCorrelation(data); got != want {
+		t.Errorf("serial correlation = %v, batch = %v", got, want)
+	}
+	if got, want := a.entropy.Entropy(), stats.ShannonEntropy(data); got != want {
+		t.Errorf("entropy = %v, batch = %v", got, want)
+	}
+	if got, want := a.chi.ChiSquare(), stats.ChiSquareUniform(data); got != want {
+		t.Errorf("chi-square = %v, batch = %v", got, want)
+	}
+	if m := a.mean(); math.Abs(m-127.5) > 1 {
+		t.Errorf("mean = %v, want about 127.5", m)
+	}
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"slices"
 
 	"github.com/spf13/cobra"
 	"github.com/vrypan/r30r2/rand"
@@ -14,18 +15,36 @@ var (
 	asciiWidth       int
 	asciiChar0       string
 	asciiChar1       string
+	asciiPNG         string
+	asciiScale       int
+	asciiSVG         string
+	asciiColor0      string
+	asciiColor1      string
+	asciiGIF         string
+	asciiFrames      int
+	asciiHeat        bool
+	asciiDensity     bool
+	asciiRule        uint64
+	asciiRadius      int
+	asciiImpulse     bool
 )
 
 var asciiCmd = &cobra.Command{
-	Use:   "ascii",
-	Short: "Visualize RNG output as ASCII art",
-	Long: `Visualize the bit patterns of random numbers generated by R30R2.
+	Use:     "ascii",
+	Aliases: []string{"visualize"},
+	Short:   "Visualize the Rule 30 strip as ASCII art",
+	Long: `Visualize the cellular automaton behind R30R2.
 
-Each row shows the bits from one Read() call (32 bytes = 256 bits max).
-This helps understand the bit distribution and pattern of the RNG output.
+Each row is one generation of the circular 256-cell strip, starting with the
+seeded strip; each row is produced from the one above it by a single step of
+the radius-2 Rule 30 variant. This shows the automaton itself, before output
+mixing.
+
+Row g (from 1) is bytes 32(g-1) to 32g-1 of the library's ReadCellOrder for
+the same seed, cells left to right, the first cell of each byte in bit 7.
 
 Examples:
-  # Default visualization (50 outputs, full width)
+  # Default visualization (50 generations, full width)
   r30r2 ascii
 
   # Narrower view for terminals
@@ -38,73 +57,253 @@ Examples:
   r30r2 ascii --seed=12345
 
   # Compact 0/1 display
-  r30r2 ascii --char0="0" --char1="1"`,
+  r30r2 ascii --char0="0" --char1="1"
+
+  # Color columns by how often they flip (blue: rarely, red: often)
+  r30r2 ascii --width=128 --heat
+
+  # Add the density of 1s per generation and a summary, to spot bad seeds
+  r30r2 ascii --density
+
+  # Compare with other rules: classic Rule 30, or Rule 90 (radius 1)
+  r30r2 ascii --radius=1 --rule=30
+  r30r2 ascii --radius=1 --rule=90
+
+  # The textbook triangle, grown from a single center cell
+  r30r2 ascii --impulse --radius=1 --rule=30 --char0=" "
+
+  # Save as a PNG image, 4x4 pixels per cell
+  r30r2 ascii --generations=256 --png=rule30.png --scale=4
+
+  # Save as an SVG drawing with custom colors
+  r30r2 ascii --generations=128 --svg=rule30.svg --scale=8 --color0="#fff" --color1="navy"
+
+  # Animate 100 generations of the first 64 cells as a ring
+  r30r2 ascii --gif=ring.gif --frames=100 --width=64`,
 	Run: func(cmd *cobra.Command, args []string) {
-		visualize(asciiSeed, asciiGenerations, asciiWidth, asciiChar0, asciiChar1)
+		rng, err := asciiRNG()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if asciiPNG != "" {
+			if err := writePNG(asciiPNG, rng, asciiGenerations, asciiWidth, asciiScale); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+		if asciiSVG != "" {
+			if err := writeSVG(asciiSVG, rng, asciiGenerations, asciiWidth, asciiScale, asciiColor0, asciiColor1); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+		if asciiGIF != "" {
+			if err := writeGIF(asciiGIF, rng, asciiFrames, asciiWidth, asciiScale); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+		visualize(rng, asciiSeed, asciiGenerations, asciiWidth, asciiChar0, asciiChar1, asciiHeat, asciiDensity)
 	},
 }
 
 func init() {
 	asciiCmd.Flags().Uint64Var(&asciiSeed, "seed", 1, "RNG seed")
-	asciiCmd.Flags().IntVar(&asciiGenerations, "generations", 50, "Number of outputs to display")
-	asciiCmd.Flags().IntVar(&asciiWidth, "width", 256, "Width in bits (max 256)")
-	asciiCmd.Flags().StringVar(&asciiChar0, "char0", "░", "Character for 0 bits")
-	asciiCmd.Flags().StringVar(&asciiChar1, "char1", "█", "Character for 1 bits")
+	asciiCmd.Flags().IntVar(&asciiGenerations, "generations", 50, "Number of generations to display")
+	asciiCmd.Flags().IntVar(&asciiWidth, "width", 256, "Width in cells (max 256)")
+	asciiCmd.Flags().StringVar(&asciiChar0, "char0", "░", "Character for 0 cells")
+	asciiCmd.Flags().StringVar(&asciiChar1, "char1", "█", "Character for 1 cells")
+	asciiCmd.Flags().StringVar(&asciiPNG, "png", "", "Write a PNG image to this file instead of printing")
+	asciiCmd.Flags().IntVar(&asciiScale, "scale", 1, "Pixels per cell side in image output")
+	asciiCmd.Flags().StringVar(&asciiSVG, "svg", "", "Write an SVG drawing to this file instead of printing")
+	asciiCmd.Flags().StringVar(&asciiColor0, "color0", "white", "SVG fill color for 0 cells")
+	asciiCmd.Flags().StringVar(&asciiColor1, "color1", "black", "SVG fill color for 1 cells")
+	asciiCmd.Flags().StringVar(&asciiGIF, "gif", "", "Write an animated GIF of the strip as a ring to this file")
+	asciiCmd.Flags().IntVar(&asciiFrames, "frames", 50, "Number of generations to animate in GIF output")
+	asciiCmd.Flags().Uint64Var(&asciiRule, "rule", rand.RuleR30R2, "Rule number to evolve the strip with (see --radius)")
+	asciiCmd.Flags().IntVar(&asciiRadius, "radius", 2, "Neighborhood radius of --rule: 1 for 8-bit rules, 2 for 32-bit rules")
+	asciiCmd.Flags().BoolVar(&asciiImpulse, "impulse", false, "Start from the single center cell 128 instead of the seeded strip (--seed is ignored)")
+	asciiCmd.Flags().BoolVar(&asciiDensity, "density", false, "Print the fraction of 1s per generation and a density summary")
+	asciiCmd.Flags().BoolVar(&asciiHeat, "heat", false, "Color cells by how often their column flips, using ANSI 256-color codes")
 }
 
-// visualize displays RNG output as ASCII art
-func visualize(seed uint64, generations, width int, char0, char1 string) {
+// asciiRNG creates the strip to display from the seed, rule and impulse
+// flags
+func asciiRNG() (*rand.RNG, error) {
+	if asciiRadius == 2 && asciiRule == rand.RuleR30R2 {
+		if asciiImpulse {
+			return rand.NewImpulse(rand.DefaultWidth), nil
+		}
+		return rand.New(asciiSeed), nil
+	}
+
+	rng, err := rand.NewWithRule(asciiSeed, asciiRadius, asciiRule)
+	if err != nil || !asciiImpulse {
+		return rng, err
+	}
+	return rng, rng.SetState(rand.NewImpulse(rand.DefaultWidth).CopyState())
+}
+
+// visualize displays successive generations of the strip as ASCII art
+// With heat set, each cell is colored by how often its column flips over the
+// displayed generations; with density set, each row ends with its fraction
+// of 1s and a summary follows the grid.
+func visualize(rng *rand.RNG, seed uint64, generations, width int, char0, char1 string, heat, density bool) {
 	if width < 1 || width > 256 {
 		fmt.Fprintf(os.Stderr, "Error: width must be between 1 and 256\n")
 		os.Exit(1)
 	}
-
-	// Create RNG
-	rng := rand.New(seed)
-
-	// Calculate bytes needed for requested width
-	bytesNeeded := (width + 7) / 8
-	if bytesNeeded > 32 {
-		bytesNeeded = 32
+	if generations < 0 {
+		fmt.Fprintf(os.Stderr, "Error: generations must not be negative\n")
+		os.Exit(1)
 	}
 
 	// Print header
-	fmt.Printf("R30R2 RNG Output Visualization\n")
-	fmt.Printf("Seed: %d | Outputs: %d | Width: %d bits\n", seed, generations, width)
-	fmt.Printf("Showing random output bit patterns\n")
+	fmt.Printf("R30R2 Cellular Automaton Visualization\n")
+	if asciiImpulse {
+		fmt.Printf("Impulse at cell %d | Generations: %d | Width: %d cells\n", rand.DefaultWidth/2, generations, width)
+	} else {
+		fmt.Printf("Seed: %d | Generations: %d | Width: %d cells\n", seed, generations, width)
+	}
+	if asciiRadius != 2 || asciiRule != rand.RuleR30R2 {
+		fmt.Printf("Rule: %d (radius %d)\n", asciiRule, asciiRadius)
+	}
+	fmt.Printf("Showing the first %d cells of the strip, one generation per row\n", width)
+	strips := stripGenerations(rng, generations)
+	d := gridDensity(strips, width)
+	var colors []int
+	if heat {
+		colors = heatColors(columnFlips(strips, width), generations-1)
+		fmt.Printf("Colored by column flips: \x1b[38;5;%dmnever\x1b[0m to \x1b[38;5;%dmevery generation\x1b[0m\n",
+			heatRamp[0], heatRamp[len(heatRamp)-1])
+	}
 	fmt.Println()
 
-	// Display outputs
-	buf := make([]byte, bytesNeeded)
-	for gen := 0; gen < generations; gen++ {
-		// Generate random bytes
-		rng.Read(buf)
-
-		// Print output number (padded)
+	// Display generations, starting from the seeded strip
+	for gen, strip := range strips {
+		// Print generation number (padded)
 		fmt.Printf("%4d │ ", gen)
 
-		// Print bits from the random output
-		bitsDisplayed := 0
-		for byteIdx := 0; byteIdx < len(buf) && bitsDisplayed < width; byteIdx++ {
-			b := buf[byteIdx]
-			bitsInThisByte := 8
-			if bitsDisplayed+bitsInThisByte > width {
-				bitsInThisByte = width - bitsDisplayed
+		// Print cells left to right
+		for c := 0; c < width; c++ {
+			if heat {
+				fmt.Printf("\x1b[38;5;%dm", colors[c])
 			}
-
-			for bit := 0; bit < bitsInThisByte; bit++ {
-				if b&1 == 1 {
-					fmt.Print(char1)
-				} else {
-					fmt.Print(char0)
-				}
-				b >>= 1
+			if cellAt(strip, c) {
+				fmt.Print(char1)
+			} else {
+				fmt.Print(char0)
 			}
-			bitsDisplayed += bitsInThisByte
 		}
+		if heat {
+			fmt.Print("\x1b[0m")
+		}
+		if density {
+			fmt.Printf(" │ %.3f", d.rows[gen])
+		}
+		fmt.Println()
+	}
+
+	if density && generations > 0 {
 		fmt.Println()
+		fmt.Printf("Density of 1s: %.4f overall, generations %.3f to %.3f\n", d.overall, slices.Min(d.rows), slices.Max(d.rows))
+		fmt.Printf("Column density: min %.3f (cell %d), max %.3f (cell %d)\n",
+			d.columns[d.minCol], d.minCol, d.columns[d.maxCol], d.maxCol)
 	}
 
 	fmt.Println()
-	fmt.Printf("Displayed %d random outputs from R30R2 RNG\n", generations)
+	fmt.Printf("Displayed %d generations of the R30R2 strip\n", generations)
+}
+
+// stripGenerations returns rng's current strip followed by the next
+// generations-1 generations
+func stripGenerations(rng *rand.RNG, generations int) [][]uint64 {
+	strips := make([][]uint64, 0, generations)
+	for gen := 0; gen < generations; gen++ {
+		if gen == 0 {
+			strips = append(strips, rng.CopyState())
+		} else {
+			strips = append(strips, rng.StepGeneration())
+		}
+	}
+	return strips
+}
+
+// columnFlips counts, for each of the first width cells, how many times the
+// cell changes value between consecutive strips
+func columnFlips(strips [][]uint64, width int) []int {
+	flips := make([]int, width)
+	for gen := 1; gen < len(strips); gen++ {
+		for c := range flips {
+			if cellAt(strips[gen], c) != cellAt(strips[gen-1], c) {
+				flips[c]++
+			}
+		}
+	}
+	return flips
+}
+
+// densitySummary is the fraction of 1s in a grid of generations
+type densitySummary struct {
+	rows           []float64 // per generation
+	columns        []float64 // per cell
+	overall        float64
+	minCol, maxCol int // the sparsest and densest cells
+}
+
+// gridDensity summarises the density of 1s in the first width cells of strips
+func gridDensity(strips [][]uint64, width int) densitySummary {
+	d := densitySummary{rows: make([]float64, len(strips)), columns: make([]float64, width)}
+	if len(strips) == 0 {
+		return d
+	}
+	ones := 0
+	for gen, strip := range strips {
+		n := 0
+		for c := 0; c < width; c++ {
+			if cellAt(strip, c) {
+				n++
+				d.columns[c]++
+			}
+		}
+		d.rows[gen] = float64(n) / float64(width)
+		ones += n
+	}
+	d.overall = float64(ones) / float64(width*len(strips))
+	for c := range d.columns {
+		d.columns[c] /= float64(len(strips))
+		if d.columns[c] < d.columns[d.minCol] {
+			d.minCol = c
+		}
+		if d.columns[c] > d.columns[d.maxCol] {
+			d.maxCol = c
+		}
+	}
+	return d
+}
+
+// heatRamp is a blue to red gradient of ANSI 256-color codes
+var heatRamp = []int{21, 27, 33, 39, 45, 51, 50, 49, 48, 47, 46, 82, 118, 154, 190, 226, 220, 214, 208, 202, 196}
+
+// heatColors maps flip counts out of at most maxFlips to heatRamp colors
+func heatColors(flips []int, maxFlips int) []int {
+	colors := make([]int, len(flips))
+	for c, f := range flips {
+		i := 0
+		if maxFlips > 0 {
+			i = f * (len(heatRamp) - 1) / maxFlips
+		}
+		colors[c] = heatRamp[i]
+	}
+	return colors
+}
+
+// cellAt reports whether cell c of a strip returned by CopyState is set
+func cellAt(strip []uint64, c int) bool {
+	return strip[c/64]>>(63-c%64)&1 == 1
 }
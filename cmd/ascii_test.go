@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/vrypan/r30r2/rand"
+)
+
+func TestReadCellOrderMatchesVisualization(t *testing.T) {
+	const seed, gens = 42, 8
+
+	// Row 0 of the visualization is the seeded strip; ReadCellOrder starts
+	// with the generation after it
+	rows := stripGenerations(rand.New(seed), gens+1)[1:]
+	p := make([]byte, 32*gens)
+	rand.New(seed).ReadCellOrder(p)
+
+	for g, strip := range rows {
+		for c := 0; c < 256; c++ {
+			bit := p[g*32+c/8]>>(7-c%8)&1 == 1
+			if bit != cellAt(strip, c) {
+				t.Fatalf("row %d cell %d: byte bit %v, displayed %v", g+1, c, bit, cellAt(strip, c))
+			}
+		}
+	}
+}
+
+func TestColumnFlips(t *testing.T) {
+	// Column 0 never changes, column 1 flips every generation, column 2
+	// twice, column 3 once and column 4 is always clear
+	strips := [][]uint64{
+		{0b1010 << 60, 0, 0, 0},
+		{0b1110 << 60, 0, 0, 0},
+		{0b1000 << 60, 0, 0, 0},
+		{0b1111 << 60, 0, 0, 0},
+	}
+	want := []int{0, 3, 2, 1, 0}
+	got := columnFlips(strips, 5)
+	if !slices.Equal(got, want) {
+		t.Errorf("columnFlips = %v, want %v", got, want)
+	}
+
+	// Flip counts scale linearly onto the ramp
+	wantColors := []int{heatRamp[0], heatRamp[20], heatRamp[13], heatRamp[6], heatRamp[0]}
+	if colors := heatColors(got, 3); !slices.Equal(colors, wantColors) {
+		t.Errorf("heatColors = %v, want %v", colors, wantColors)
+	}
+	if colors := heatColors([]int{0}, 0); colors[0] != heatRamp[0] {
+		t.Errorf("single generation colored %d, want %d", colors[0], heatRamp[0])
+	}
+}
+
+func TestGridDensity(t *testing.T) {
+	d := gridDensity(stripGenerations(rand.New(1), 256), 256)
+	if d.overall < 0.45 || d.overall > 0.55 {
+		t.Errorf("overall density %.4f, want about 0.5", d.overall)
+	}
+	if len(d.rows) != 256 || len(d.columns) != 256 {
+		t.Fatalf("got %d row and %d column densities, want 256 each", len(d.rows), len(d.columns))
+	}
+	// The sparse seeded strip fills in over the warmup period
+	for gen := rand.DefaultWarmup; gen < len(d.rows); gen++ {
+		if v := d.rows[gen]; v < 0.3 || v > 0.7 {
+			t.Errorf("generation %d density %.3f is far from 0.5", gen, v)
+		}
+	}
+	if lo, hi := d.columns[d.minCol], d.columns[d.maxCol]; lo < 0.3 || hi > 0.7 || lo > hi {
+		t.Errorf("column densities from %.3f to %.3f, want both near 0.5", lo, hi)
+	}
+
+	// A known grid: an all-zero strip, then cells 0 and 2 set
+	d = gridDensity([][]uint64{{0, 0, 0, 0}, {0b101 << 61, 0, 0, 0}}, 4)
+	if !slices.Equal(d.rows, []float64{0, 0.5}) || d.overall != 0.25 {
+		t.Errorf("rows %v overall %v, want [0 0.5] 0.25", d.rows, d.overall)
+	}
+	if !slices.Equal(d.columns, []float64{0.5, 0, 0.5, 0}) || d.minCol != 1 || d.maxCol != 0 {
+		t.Errorf("columns %v min %d max %d", d.columns, d.minCol, d.maxCol)
+	}
+}
+
+func TestRule90Sierpinski(t *testing.T) {
+	const center, gens = 128, 32
+	rng, err := rand.NewWithRule(0, 1, 90)
+	if err != nil {
+		t.Fatal(err)
+	}
+	impulse := make([]uint64, 4)
+	impulse[center/64] = 1 << (63 - center%64)
+	if err := rng.SetState(impulse); err != nil {
+		t.Fatal(err)
+	}
+
+	// Rule 90 from one cell draws Pascal's triangle mod 2: offset k in
+	// generation g is set when g+k is even and C(g, (g+k)/2) is odd
+	for g, strip := range stripGenerations(rng, gens) {
+		for k := -gens; k <= gens; k++ {
+			want := false
+			if j := (g + k) / 2; (g+k)%2 == 0 && j >= 0 && j <= g {
+				want = j&g == j // Lucas' theorem
+			}
+			if got := cellAt(strip, center+k); got != want {
+				t.Fatalf("generation %d offset %d = %v, want %v", g, k, got, want)
+			}
+		}
+	}
+}
+
+func TestASCIIRuleValidation(t *testing.T) {
+	defer func(rule uint64, radius int) { asciiRule, asciiRadius = rule, radius }(asciiRule, asciiRadius)
+
+	asciiRule, asciiRadius = 256, 1 // radius 1 has an 8-bit table
+	if _, err := asciiRNG(); err == nil {
+		t.Error("rule 256 accepted for radius 1")
+	}
+	asciiRule, asciiRadius = 90, 1
+	if _, err := asciiRNG(); err != nil {
+		t.Errorf("rule 90 radius 1: %v", err)
+	}
+}
+
+func TestASCIIImpulse(t *testing.T) {
+	defer func(rule uint64, radius int, impulse bool) {
+		asciiRule, asciiRadius, asciiImpulse = rule, radius, impulse
+	}(asciiRule, asciiRadius, asciiImpulse)
+
+	// Classic Rule 30 from one cell
+	asciiRule, asciiRadius, asciiImpulse = 30, 1, true
+	rng, err := asciiRNG()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for g, strip := range stripGenerations(rng, 4) {
+		var row []byte
+		for c := 128 - g; c <= 128+g; c++ {
+			if cellAt(strip, c) {
+				row = append(row, '1')
+			} else {
+				row = append(row, '0')
+			}
+		}
+		if want := []string{"1", "111", "11001", "1101111"}[g]; string(row) != want {
+			t.Errorf("generation %d = %s, want %s", g, row, want)
+		}
+	}
+}
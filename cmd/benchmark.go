@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	cryptorand "crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	mathrand "math/rand"
+	mathrandv2 "math/rand/v2"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/vrypan/r30r2/bench"
+	"github.com/vrypan/r30r2/internal/compare"
+	"github.com/vrypan/r30r2/rand"
+)
+
+// benchmarkCalls is how many Uint64 calls each generator is timed for
+const benchmarkCalls = 10000000
+
+var benchmarkCmd = &cobra.Command{
+	Use:   "benchmark",
+	Short: "Compare Uint64() latency with other generators",
+	Long: `Measure the per-call latency of Uint64() for R30R2, math/rand,
+math/rand/v2 and crypto/rand, with spread and percentiles across batches.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Progress goes nowhere with --quiet; the summary is still printed
+		var log io.Writer = os.Stdout
+		if quiet {
+			log = io.Discard
+		}
+
+		fmt.Fprintln(log, "═══════════════════════════════════════════════════════════")
+		fmt.Fprintln(log, "  Uint64() Benchmark - Latency per Call")
+		fmt.Fprintln(log, "═══════════════════════════════════════════════════════════")
+		fmt.Fprintln(log)
+
+		// crypto/rand Uint64 wrapper
+		cryptoUint64 := func() uint64 {
+			var buf [8]byte
+			cryptorand.Read(buf[:])
+			return binary.LittleEndian.Uint64(buf[:])
+		}
+
+		generators := []struct {
+			name string
+			gen  func() uint64
+		}{
+			{"R30R2RNG", rand.New(12345).Uint64},
+			{"math/rand", mathrand.New(mathrand.NewSource(12345)).Uint64},
+			{"math/rand/v2", mathrandv2.New(mathrandv2.NewPCG(12345, 12345)).Uint64},
+			{"crypto/rand", cryptoUint64},
+		}
+
+		fmt.Fprintf(log, "Testing %s in batches of %d...\n", compare.FormatCalls(benchmarkCalls), bench.Uint64BatchSize)
+		var results []bench.BenchResult
+		for _, g := range generators {
+			result := bench.RunUint64Benchmark(g.name, benchmarkCalls, g.gen)
+			results = append(results, result)
+			fmt.Fprintf(log, "  ✓ %-14s %6.1f ns/call\n", g.name+":", result.NsPerCall)
+		}
+		fmt.Fprintln(log)
+
+		fmt.Println("═══════════════════════════════════════════════════════════")
+		fmt.Println("  Summary Table (ns/call)")
+		fmt.Println("═══════════════════════════════════════════════════════════")
+		fmt.Println()
+
+		fmt.Printf("%-15s │ %-12s │ %-10s │ %-10s │ %-10s │ %-10s\n", "RNG", "Mean", "Std dev", "p50", "p99", "Relative")
+		fmt.Println("────────────────┼──────────────┼────────────┼────────────┼────────────┼────────────")
+
+		// R30R2RNG is the baseline
+		baseline := results[0].NsPerCall
+		for _, r := range results {
+			fmt.Printf("%-15s │ %9.1f ns │ %7.2f ns │ %7.1f ns │ %7.1f ns │ %8.2f×\n",
+				r.Name, r.NsPerCall, r.StdDev, r.P50, r.P99, r.NsPerCall/baseline)
+		}
+
+		fmt.Println()
+		fmt.Println("═══════════════════════════════════════════════════════════")
+		fmt.Println()
+
+		fmt.Println("Notes:")
+		fmt.Println("  • R30R2RNG:    1D CA (Rule 30), 256-bit state, deterministic")
+		fmt.Println("  • math/rand:    Legacy PRNG (LFSR), deterministic")
+		fmt.Println("  • math/rand/v2: Modern PRNG (PCG), deterministic")
+		fmt.Println("  • crypto/rand:  Hardware-accelerated CSPRNG")
+		fmt.Println("  • Lower ns/call is better (faster)")
+		fmt.Printf("  • Std dev and percentiles are across batches of %d calls\n", bench.Uint64BatchSize)
+		fmt.Println()
+	},
+}
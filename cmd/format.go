@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+)
+
+// This file contains the output encodings of the raw command.
+
+// formats lists the values accepted by --format
+var formats = []string{"raw", "hex", "base64", "c"}
+
+// newEncoder wraps w so bytes written to it are encoded in format
+// Encoders stream: each write is encoded straight to w and at most a partial
+// base64 group is held back, so unlimited output runs in constant memory.
+// Close flushes any pending output and terminates the encoding; it does not
+// close w.
+func newEncoder(format string, w io.Writer) (io.WriteCloser, error) {
+	switch format {
+	case "raw":
+		return nopCloser{w}, nil
+	case "hex":
+		return &lineEnder{Writer: hex.NewEncoder(w), w: w}, nil
+	case "base64":
+		enc := base64.NewEncoder(base64.StdEncoding, w)
+		return &lineEnder{Writer: enc, flush: enc, w: w}, nil
+	case "c":
+		return &cArrayWriter{w: w}, nil
+	}
+	return nil, fmt.Errorf("unknown format %q (want one of %v)", format, formats)
+}
+
+// nopCloser adds a no-op Close to a writer
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
+
+// lineEnder writes through an encoder and ends the output with a newline
+type lineEnder struct {
+	io.Writer
+	flush io.Closer // flushes the encoder, if it buffers
+	w     io.Writer // the underlying writer
+}
+
+func (l *lineEnder) Close() error {
+	if l.flush != nil {
+		if err := l.flush.Close(); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(l.w, "\n")
+	return err
+}
+
+// cArrayWriter formats bytes as a C array definition, 12 bytes per line
+type cArrayWriter struct {
+	w io.Writer
+	n int // bytes written so far
+}
+
+func (c *cArrayWriter) Write(p []byte) (int, error) {
+	if c.n == 0 && len(p) > 0 {
+		if _, err := io.WriteString(c.w, "unsigned char data[] = {"); err != nil {
+			return 0, err
+		}
+	}
+
+	line := make([]byte, 0, 8*len(p)+len(p)/12*4)
+	for _, b := range p {
+		if c.n > 0 {
+			line = append(line, ',')
+		}
+		if c.n%12 == 0 {
+			line = append(line, "\n   "...)
+		}
+		line = fmt.Appendf(line, " 0x%02x", b)
+		c.n++
+	}
+	if _, err := c.w.Write(line); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (c *cArrayWriter) Close() error {
+	if c.n == 0 {
+		_, err := io.WriteString(c.w, "unsigned char data[] = {};\n")
+		return err
+	}
+	_, err := io.WriteString(c.w, "\n};\n")
+	return err
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha3"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/vrypan/r30r2/rand"
+	"golang.org/x/crypto/blake2b"
+)
+
+// This file contains the digest printed by --hash.
+
+// hashNames lists the values accepted by --hash
+var hashNames = []string{"sha256", "sha512", "sha3-256", "blake2b"}
+
+// newHash returns a new hash.Hash for the named algorithm
+func newHash(name string) (hash.Hash, error) {
+	switch name {
+	case "sha256":
+		return sha256.New(), nil
+	case "sha512":
+		return sha512.New(), nil
+	case "sha3-256":
+		return sha3.New256(), nil
+	case "blake2b":
+		// BLAKE2b-512, the b2sum default
+		return blake2b.New512(nil)
+	}
+	return nil, fmt.Errorf("unknown hash %q (want one of %v)", name, hashNames)
+}
+
+// writeDigest streams count bytes from rng through the named hash and writes
+// the hex digest to w, followed by a newline
+func writeDigest(ctx context.Context, w io.Writer, name string, rng *rand.RNG, count int) error {
+	h, err := newHash(name)
+	if err != nil {
+		return err
+	}
+	if _, err := generateBytes(ctx, h, rng, count); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%x\n", h.Sum(nil))
+	return err
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha512"
+	"fmt"
+	"testing"
+
+	"github.com/vrypan/r30r2/rand"
+)
+
+func TestWriteDigest(t *testing.T) {
+	// Reference from: r30r2 --seed 1 --bytes 1000000 | sha256sum
+	const want = "1f4e43c7beba17015a7b3482d5f79c8a845b158eb41578db5085ffbe976dd4af\n"
+	var out bytes.Buffer
+	if err := writeDigest(context.Background(), &out, "sha256", rand.New(1), 1000000); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != want {
+		t.Errorf("sha256 digest = %q, want %q", out.String(), want)
+	}
+
+	// Reference from: r30r2 --seed 1 --bytes 1000000 | b2sum
+	const wantBlake = "a18420f5bd0d06155531dd1fa8b3e22cd4b2033c48ecb8c43cef41521ae290b5" +
+		"ab3fb08568aab34b7e8eb1239db05e8ad09a8cc2e3e853f617495ae36b199a99\n"
+	out.Reset()
+	if err := writeDigest(context.Background(), &out, "blake2b", rand.New(1), 1000000); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != wantBlake {
+		t.Errorf("blake2b digest = %q, want %q", out.String(), wantBlake)
+	}
+
+	out.Reset()
+	if err := writeDigest(context.Background(), &out, "sha512", rand.New(7), 5000); err != nil {
+		t.Fatal(err)
+	}
+	if ref := fmt.Sprintf("%x\n", sha512.Sum512(rand.New(7).Bytes(5000))); out.String() != ref {
+		t.Errorf("sha512 digest = %q, want %q", out.String(), ref)
+	}
+
+	if err := writeDigest(context.Background(), &out, "md5", rand.New(1), 10); err == nil {
+		t.Error("unknown hash accepted")
+	}
+}
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/vrypan/r30r2/rand"
+)
+
+// This file contains image output for the ascii visualizer.
+
+// cellPalette maps 0 cells to white and 1 cells to black
+var cellPalette = color.Palette{color.White, color.Black}
+
+// ringPalette adds light gray for 0 cells so the whole ring stays visible
+var ringPalette = color.Palette{color.White, color.Black, color.Gray{Y: 0xDD}}
+
+// renderStrip draws one row per strip and one column per cell, each cell a
+// scale x scale block
+func renderStrip(strips [][]uint64, width, scale int) *image.Paletted {
+	img := image.NewPaletted(image.Rect(0, 0, width*scale, len(strips)*scale), cellPalette)
+	for gen, strip := range strips {
+		for c := 0; c < width; c++ {
+			if !cellAt(strip, c) {
+				continue // already white
+			}
+			for y := gen * scale; y < (gen+1)*scale; y++ {
+				for x := c * scale; x < (c+1)*scale; x++ {
+					img.SetColorIndex(x, y, 1)
+				}
+			}
+		}
+	}
+	return img
+}
+
+// checkImageSize validates the dimensions of an image of the strip
+func checkImageSize(generations, width, scale int) error {
+	if width < 1 || width > 256 {
+		return fmt.Errorf("width must be between 1 and 256")
+	}
+	if generations < 1 || scale < 1 {
+		return fmt.Errorf("generations and scale must be positive")
+	}
+	return nil
+}
+
+// writePNG renders generations of the strip to a PNG file at path
+func writePNG(path string, rng *rand.RNG, generations, width, scale int) error {
+	if err := checkImageSize(generations, width, scale); err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	img := renderStrip(stripGenerations(rng, generations), width, scale)
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// renderSVG writes a standalone SVG drawing of strips with one square rect
+// of side scale per cell, filled with color0 or color1
+func renderSVG(w io.Writer, strips [][]uint64, width, scale int, color0, color1 string) error {
+	bw := bufio.NewWriter(w)
+	fill0, fill1 := xmlAttr(color0), xmlAttr(color1)
+	imgW, imgH := width*scale, len(strips)*scale
+	fmt.Fprintf(bw, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	fmt.Fprintf(bw, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\" shape-rendering=\"crispEdges\">\n", imgW, imgH, imgW, imgH)
+	for gen, strip := range strips {
+		for c := 0; c < width; c++ {
+			fill := fill0
+			if cellAt(strip, c) {
+				fill = fill1
+			}
+			fmt.Fprintf(bw, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\"/>\n", c*scale, gen*scale, scale, scale, fill)
+		}
+	}
+	fmt.Fprintf(bw, "</svg>\n")
+	return bw.Flush()
+}
+
+// xmlAttr escapes s for use inside a double-quoted XML attribute
+func xmlAttr(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// writeSVG renders generations of the strip to an SVG file at path
+func writeSVG(path string, rng *rand.RNG, generations, width, scale int, color0, color1 string) error {
+	if err := checkImageSize(generations, width, scale); err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := renderSVG(f, stripGenerations(rng, generations), width, scale, color0, color1); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// renderRing writes an animated GIF with one frame per strip, drawing the
+// first width cells around a circle to show the strip's circular topology
+// Cell 0 is at the top and cell indices increase clockwise.
+func renderRing(w io.Writer, strips [][]uint64, width, scale int) error {
+	dot := 4 * scale // cell diameter in pixels
+	radius := max(float64(width*dot)*1.5/(2*math.Pi), float64(2*dot))
+	side := int(2*radius) + 2*dot
+	center := float64(side) / 2
+
+	anim := &gif.GIF{}
+	for _, strip := range strips {
+		img := image.NewPaletted(image.Rect(0, 0, side, side), ringPalette)
+		for c := 0; c < width; c++ {
+			index := uint8(2) // gray
+			if cellAt(strip, c) {
+				index = 1 // black
+			}
+			angle := 2*math.Pi*float64(c)/float64(width) - math.Pi/2
+			fillDisc(img, center+radius*math.Cos(angle), center+radius*math.Sin(angle), float64(dot)/2, index)
+		}
+		anim.Image = append(anim.Image, img)
+		anim.Delay = append(anim.Delay, 10) // 1/10 s per generation
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+// fillDisc sets every pixel of img within r of (x, y) to palette entry index
+func fillDisc(img *image.Paletted, x, y, r float64, index uint8) {
+	for py := int(y - r); py <= int(y+r); py++ {
+		for px := int(x - r); px <= int(x+r); px++ {
+			dx, dy := float64(px)+0.5-x, float64(py)+0.5-y
+			if dx*dx+dy*dy <= r*r {
+				img.SetColorIndex(px, py, index)
+			}
+		}
+	}
+}
+
+// writeGIF renders frames generations of the strip as a ring animation to a
+// GIF file at path
+func writeGIF(path string, rng *rand.RNG, frames, width, scale int) error {
+	if err := checkImageSize(frames, width, scale); err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := renderRing(f, stripGenerations(rng, frames), width, scale); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vrypan/r30r2/rand"
+)
+
+func TestWritePNG(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "strip.png")
+	if err := writePNG(path, rand.New(1), 10, 64, 3); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if b := img.Bounds(); b.Dx() != 64*3 || b.Dy() != 10*3 {
+		t.Fatalf("image is %dx%d, want 192x30", b.Dx(), b.Dy())
+	}
+
+	// Seed 1 starts with cells 0-62 clear; cell 0 is set one generation later
+	black := color.GrayModel.Convert(color.Black)
+	if c := color.GrayModel.Convert(img.At(0, 0)); c == black {
+		t.Error("cell 0 of generation 0 is black, want white")
+	}
+	for _, p := range [][2]int{{0, 3}, {2, 5}} { // both corners of the block
+		if c := color.GrayModel.Convert(img.At(p[0], p[1])); c != black {
+			t.Errorf("pixel %v of cell 0, generation 1 is not black", p)
+		}
+	}
+}
+
+func TestWritePNGRejectsBadSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "strip.png")
+	if err := writePNG(path, rand.New(1), 10, 257, 1); err == nil {
+		t.Error("width 257 accepted")
+	}
+	if err := writePNG(path, rand.New(1), 10, 64, 0); err == nil {
+		t.Error("scale 0 accepted")
+	}
+}
+
+func TestRenderSVG(t *testing.T) {
+	const generations, width = 12, 40
+	var buf bytes.Buffer
+	err := renderSVG(&buf, stripGenerations(rand.New(7), generations), width, 5, "#fff", `a"b<c`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rects := 0
+	dec := xml.NewDecoder(&buf)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("output is not valid XML: %v", err)
+		}
+		if el, ok := tok.(xml.StartElement); ok && el.Name.Local == "rect" {
+			rects++
+		}
+	}
+	if rects != generations*width {
+		t.Errorf("found %d rects, want %d", rects, generations*width)
+	}
+}
+
+func TestWriteGIF(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ring.gif")
+	if err := writeGIF(path, rand.New(3), 17, 64, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	anim, err := gif.DecodeAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anim.Image) != 17 {
+		t.Errorf("GIF has %d frames, want 17", len(anim.Image))
+	}
+}
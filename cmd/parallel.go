@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/vrypan/r30r2/rand"
+)
+
+// This file contains multi-worker generation for the raw command.
+
+// generateParallel is generateBytes spread over workers goroutines
+// Each worker owns a generator split from rng and fills every workers-th 1MB
+// chunk of the output, so the bytes depend only on rng and the worker count.
+// They differ from the single-worker stream.
+func generateParallel(ctx context.Context, w io.Writer, rng *rand.RNG, count, workers int) (int64, error) {
+	const chunkSize = 1024 * 1024 // 1MB chunks
+	gens := rng.SplitN(workers)
+	bufs := make([][]byte, workers)
+	for i := range bufs {
+		bufs[i] = make([]byte, chunkSize)
+	}
+
+	var written int64
+	for count == 0 || written < int64(count) {
+		if ctx.Err() != nil {
+			break
+		}
+
+		// Size this round's chunks; only the last round can be short
+		sizes := make([]int, workers)
+		planned := written
+		for i := range sizes {
+			sizes[i] = chunkSize
+			if count != 0 {
+				sizes[i] = int(min(int64(chunkSize), max(int64(count)-planned, 0)))
+			}
+			planned += int64(sizes[i])
+		}
+
+		var wg sync.WaitGroup
+		for i, gen := range gens {
+			wg.Go(func() { gen.Read(bufs[i][:sizes[i]]) })
+		}
+		wg.Wait()
+
+		for i, buf := range bufs {
+			m, err := w.Write(buf[:sizes[i]])
+			written += int64(m)
+			if err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
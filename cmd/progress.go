@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// This file contains progress reporting for long fixed-size generation.
+
+// progressInterval is how often progress lines are printed
+var progressInterval = time.Second
+
+// countingWriter counts the bytes written through it
+// The count is read concurrently by the progress reporter.
+type countingWriter struct {
+	w io.Writer
+	n atomic.Int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n.Add(int64(n))
+	return n, err
+}
+
+// startProgress prints the bytes written through cw out of total, the
+// throughput and an ETA every progressInterval until the returned stop
+// function is called
+// It only reads the counter, so it cannot affect the generated bytes.
+func startProgress(cw *countingWriter, total int64) (stop func()) {
+	start := time.Now()
+	ticker := time.NewTicker(progressInterval)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				n := cw.n.Load()
+				elapsed := now.Sub(start).Seconds()
+				rate := float64(n) / elapsed / (1024 * 1024)
+				eta := "unknown"
+				if n > 0 {
+					eta = time.Duration(float64(total-n) / float64(n) * elapsed * float64(time.Second)).Round(time.Second).String()
+				}
+				infof("%d / %d bytes (%.1f%%), %.1f MB/s, ETA %s\n",
+					n, total, 100*float64(n)/float64(total), rate, eta)
+			}
+		}
+	})
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vrypan/r30r2/rand"
+)
+
+// slowWriter sleeps before each write so progress has time to tick
+type slowWriter struct {
+	bytes.Buffer
+}
+
+func (w *slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(5 * time.Millisecond)
+	return w.Buffer.Write(p)
+}
+
+func TestProgressKeepsOutput(t *testing.T) {
+	var stderr bytes.Buffer
+	infoOut, progressInterval = &stderr, time.Millisecond
+	t.Cleanup(func() { infoOut, progressInterval = os.Stderr, time.Second })
+
+	const count = 8*1024*1024 + 77
+	var plain bytes.Buffer
+	if _, err := generateEncoded(context.Background(), &plain, "raw", rand.New(5), count, 1, false); err != nil {
+		t.Fatal(err)
+	}
+	var reported slowWriter
+	if _, err := generateEncoded(context.Background(), &reported, "raw", rand.New(5), count, 1, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(plain.Bytes(), reported.Bytes()) {
+		t.Error("output with --progress differs")
+	}
+	if !strings.Contains(stderr.String(), "MB/s") {
+		t.Errorf("no progress lines on stderr: %q", stderr.String())
+	}
+}
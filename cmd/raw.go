@@ -1,8 +1,14 @@
 package cmd
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"slices"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -10,13 +16,29 @@ import (
 )
 
 var (
-	rawSeed  uint64
-	rawBytes int
+	rawSeed     uint64
+	rawBytes    int
+	rawOut      string
+	rawFormat   string
+	rawSeedHex  string
+	rawSeedFile string
+	rawWorkers  int
+	rawProgress bool
+	rawAnalyze  bool
+	rawHash     string
+	rawCenter   int
+	rawWidth    int
+	rawRule     uint64
+	rawRadius   int
 )
 
+// periodCheckGens bounds the cycle search run for narrow strips
+const periodCheckGens = 1 << 20
+
 var rawCmd = &cobra.Command{
-	Use:   "raw",
-	Short: "Generate raw random bytes",
+	Use:     "raw",
+	Aliases: []string{"generate"},
+	Short:   "Generate raw random bytes",
 	Long: `Generate raw random bytes to stdout.
 
 This is the default subcommand if none is specified.
@@ -28,6 +50,33 @@ Examples:
   # Use specific seed
   r30r2 raw --seed 12345 --bytes 1048576 > random.bin
 
+  # Seed all 256 bits of the strip (up to 64 hex digits)
+  r30r2 raw --seed-hex 00112233445566778899aabbccddeeff00112233445566778899aabbccddeeff
+
+  # Fill a large file using 8 goroutines (output depends on the worker count)
+  r30r2 raw --bytes 17179869184 --workers 8 -o random.bin
+
+  # Report progress on stderr every second
+  r30r2 raw --bytes 17179869184 --progress -o random.bin
+
+  # Print an ent-style randomness report instead of the bytes
+  r30r2 raw --bytes 10485760 --analyze
+
+  # Print the SHA-256 of the output, e.g. to compare platforms
+  r30r2 raw --seed 1 --bytes 1073741824 --hash sha256
+
+  # A narrower 128-cell strip; warns on stderr if the output would cycle
+  r30r2 raw --seed 1 --width 128 --bytes 1048576 > random.bin
+
+  # Evolve the strip with another rule; warns on stderr if it cycles
+  r30r2 raw --seed 1 --radius 1 --rule 30 --bytes 1048576 > random.bin
+
+  # The classic Rule 30 center column: 4096 bits, one per generation
+  r30r2 raw --seed 1 --center-bits 4096 > center.bin
+
+  # Derive the seed from a file's SHA-256 hash
+  r30r2 raw --seed-file key.txt --bytes 1048576 > random.bin
+
   # Generate specific size with dd
   r30r2 raw --bytes 1073741824 | dd of=test.data bs=1m
 
@@ -37,78 +86,346 @@ Examples:
   # Test randomness with ent
   r30r2 raw --bytes 1048576 | ent
 
+  # Write to a file instead of stdout
+  r30r2 raw --bytes 1048576 -o random.bin
+
+  # 32 random bytes as hex, base64 or a C array
+  r30r2 raw --bytes 32 --format hex
+  r30r2 raw --bytes 32 --format base64
+  r30r2 raw --bytes 32 --format c
+
   # Default behavior (no subcommand)
   r30r2 --bytes 1024 > random.bin`,
 	Run: func(cmd *cobra.Command, args []string) {
-		// Use time-based seed if not specified
-		if rawSeed == 0 {
-			rawSeed = uint64(time.Now().UnixNano())
+		rng, seedDesc, err := rawRNG()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if rawWidth < rand.DefaultWidth || customRawRule() {
+			// Narrow strips and other rules can fall into short cycles; say
+			// so rather than silently repeating output
+			if msg := periodWarning(rng, rawBytes); msg != "" {
+				fmt.Fprintf(warnOut, "Warning: %s\n", msg)
+			}
 		}
 
-		generateBytes(rawSeed, rawBytes)
-	},
-}
-
-func init() {
-	rawCmd.Flags().Uint64Var(&rawSeed, "seed", 0, "RNG seed (default: time-based)")
-	rawCmd.Flags().IntVar(&rawBytes, "bytes", 1024, "Number of bytes to generate (0 = unlimited)")
-}
-
-// generateBytes generates and writes random bytes to stdout
-func generateBytes(seed uint64, count int) {
-	rng := rand.New(seed)
-
-	if count == 0 {
-		// Unlimited mode: stream chunks until pipe breaks
-		buf := make([]byte, 1024*1024) // 1MB chunks
-		for {
-			n, err := rng.Read(buf)
+		if rawCenter != 0 {
+			if rawCenter < 0 {
+				fmt.Fprintf(os.Stderr, "Error: --center-bits must be positive\n")
+				os.Exit(1)
+			}
+			if rawOut == "" {
+				if err := writeCenterBits(os.Stdout, rawFormat, rawSeed, rawCenter); err != nil {
+					fmt.Fprintf(os.Stderr, "Error writing: %v\n", err)
+					os.Exit(1)
+				}
+				return
+			}
+			f, err := os.Create(rawOut)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
 			}
-
-			// Write all bytes, handling partial writes
-			written := 0
-			for written < n {
-				w, err := os.Stdout.Write(buf[written:n])
-				if err != nil {
-					// Pipe closed (e.g., dd finished) - exit gracefully
-					os.Exit(0)
-				}
-				written += w
+			err = writeCenterBits(f, rawFormat, rawSeed, rawCenter)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", rawOut, err)
+				os.Exit(1)
 			}
+			infof("Wrote %d center-column bits to %s (seed %s)\n", rawCenter, rawOut, seedDesc)
+			return
 		}
-	} else {
-		// Fixed size: stream in chunks to avoid huge allocations
-		const chunkSize = 1024 * 1024 // 1MB chunks
-		buf := make([]byte, chunkSize)
-		remaining := count
-
-		for remaining > 0 {
-			toRead := chunkSize
-			if remaining < chunkSize {
-				toRead = remaining
+
+		if rawAnalyze {
+			if rawBytes <= 0 {
+				fmt.Fprintf(os.Stderr, "Error: --analyze needs a fixed --bytes count\n")
+				os.Exit(1)
 			}
+			a := newAnalyzer()
+			if _, err := generateBytes(context.Background(), a, rng, rawBytes); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			err := writeRawOut(func(w io.Writer) error {
+				a.writeReport(w)
+				return nil
+			})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
 
-			n, err := rng.Read(buf[:toRead])
+		if rawHash != "" {
+			if rawBytes <= 0 {
+				fmt.Fprintf(os.Stderr, "Error: --hash needs a fixed --bytes count\n")
+				os.Exit(1)
+			}
+			if !slices.Contains(hashNames, rawHash) {
+				fmt.Fprintf(os.Stderr, "Error: unknown hash %q (want one of %v)\n", rawHash, hashNames)
+				os.Exit(1)
+			}
+			err := writeRawOut(func(w io.Writer) error {
+				return writeDigest(context.Background(), w, rawHash, rng, rawBytes)
+			})
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
 			}
+			return
+		}
 
-			// Write all bytes from this read, handling partial writes
-			written := 0
-			for written < n {
-				w, err := os.Stdout.Write(buf[written:n])
-				if err != nil {
-					fmt.Fprintf(os.Stderr, "Error writing: %v\n", err)
-					os.Exit(1)
+		if !slices.Contains(formats, rawFormat) {
+			fmt.Fprintf(os.Stderr, "Error: unknown format %q (want one of %v)\n", rawFormat, formats)
+			os.Exit(1)
+		}
+		if rawWorkers < 1 {
+			fmt.Fprintf(os.Stderr, "Error: --workers must be at least 1\n")
+			os.Exit(1)
+		}
+		if rawFormat == "c" && rawBytes == 0 {
+			fmt.Fprintf(os.Stderr, "Error: --format c needs a fixed --bytes count\n")
+			os.Exit(1)
+		}
+
+		// Ctrl+C stops unlimited output cleanly
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		if rawOut == "" {
+			if _, err := generateEncoded(ctx, os.Stdout, rawFormat, rng, rawBytes, rawWorkers, rawProgress); err != nil {
+				if rawBytes == 0 {
+					// Pipe closed (e.g., dd finished) - exit gracefully
+					os.Exit(0)
 				}
-				written += w
+				fmt.Fprintf(os.Stderr, "Error writing: %v\n", err)
+				os.Exit(1)
 			}
+			return
+		}
+
+		f, err := os.Create(rawOut)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		n, err := generateEncoded(ctx, f, rawFormat, rng, rawBytes, rawWorkers, rawProgress)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", rawOut, err)
+			os.Exit(1)
+		}
+		infof("Wrote %d bytes to %s (seed %s)\n", n, rawOut, seedDesc)
+	},
+}
+
+func init() {
+	rawCmd.Flags().Uint64Var(&rawSeed, "seed", 0, "RNG seed (default: time-based)")
+	rawCmd.Flags().IntVar(&rawBytes, "bytes", 1024, "Number of bytes to generate (0 = unlimited)")
+	rawCmd.Flags().StringVarP(&rawOut, "out", "o", "", "Write to this file instead of stdout")
+	rawCmd.Flags().StringVar(&rawFormat, "format", "raw", "Output encoding: raw, hex, base64 or c")
+	rawCmd.Flags().StringVar(&rawSeedHex, "seed-hex", "", "Seed the full 256-bit strip with up to 64 hex digits")
+	rawCmd.Flags().StringVar(&rawSeedFile, "seed-file", "", "Seed the full strip with the SHA-256 hash of this file")
+	rawCmd.Flags().IntVar(&rawWorkers, "workers", 1, "Number of goroutines generating in parallel")
+	rawCmd.Flags().BoolVar(&rawProgress, "progress", false, "Report progress on stderr every second (fixed --bytes only)")
+	rawCmd.Flags().BoolVar(&rawAnalyze, "analyze", false, "Print an ent-style report of the output (to stdout or -o) instead of writing it")
+	rawCmd.Flags().StringVar(&rawHash, "hash", "", "Print the sha256, sha512, sha3-256 or blake2b digest of the output (to stdout or -o) instead of writing it")
+	rawCmd.Flags().IntVar(&rawWidth, "width", rand.DefaultWidth, "Strip width in cells: 128, 256, 512 or 1024 (not with --seed-hex or --seed-file)")
+	rawCmd.Flags().Uint64Var(&rawRule, "rule", rand.RuleR30R2, "Rule number to evolve the strip with (see --radius; not with --seed-hex or --seed-file)")
+	rawCmd.Flags().IntVar(&rawRadius, "radius", 2, "Neighborhood radius of --rule: 1 for 8-bit rules, 2 for 32-bit rules")
+	rawCmd.Flags().IntVar(&rawCenter, "center-bits", 0, "Output this many center-column bits, 8 generations per byte, instead of normal bytes")
+	rawCmd.MarkFlagsMutuallyExclusive("seed", "seed-hex", "seed-file")
+	rawCmd.MarkFlagsMutuallyExclusive("center-bits", "seed-hex", "seed-file")
+	// Byte seeds always build the default strip with the built-in rule
+	rawCmd.MarkFlagsMutuallyExclusive("width", "seed-hex", "seed-file")
+	rawCmd.MarkFlagsMutuallyExclusive("rule", "seed-hex", "seed-file")
+	rawCmd.MarkFlagsMutuallyExclusive("radius", "seed-hex", "seed-file")
+	rawCmd.MarkFlagsMutuallyExclusive("center-bits", "analyze", "hash")
+	// The center column is always one cell of the default strip, generated
+	// serially in one go
+	for _, name := range []string{"width", "workers", "progress", "rule", "radius"} {
+		rawCmd.MarkFlagsMutuallyExclusive("center-bits", name)
+	}
+	// Custom rules run on the default strip only, and parallel workers are
+	// split off with the built-in rule
+	for _, name := range []string{"width", "workers"} {
+		rawCmd.MarkFlagsMutuallyExclusive("rule", name)
+		rawCmd.MarkFlagsMutuallyExclusive("radius", name)
+	}
+	rawCmd.MarkFlagsMutuallyExclusive("analyze", "hash")
+	// Reports and digests are plain text computed serially
+	for _, name := range []string{"format", "workers", "progress"} {
+		rawCmd.MarkFlagsMutuallyExclusive("analyze", name)
+		rawCmd.MarkFlagsMutuallyExclusive("hash", name)
+	}
+}
+
+// writeRawOut calls write with stdout, or with the file named by -o
+func writeRawOut(write func(io.Writer) error) error {
+	if rawOut == "" {
+		return write(os.Stdout)
+	}
+	f, err := os.Create(rawOut)
+	if err != nil {
+		return err
+	}
+	err = write(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// rawRNG creates the generator selected by the seed flags, and a description
+// of the seed for messages
+func rawRNG() (*rand.RNG, string, error) {
+	if rawSeedHex != "" {
+		rng, err := rngFromHex(rawSeedHex)
+		return rng, rawSeedHex, err
+	}
+	if rawSeedFile != "" {
+		rng, err := rngFromFile(rawSeedFile)
+		return rng, "from " + rawSeedFile, err
+	}
+
+	// Use time-based seed if not specified
+	if rawSeed == 0 {
+		rawSeed = uint64(time.Now().UnixNano())
+	}
+	if customRawRule() {
+		rng, err := rand.NewWithRule(rawSeed, rawRadius, rawRule)
+		return rng, fmt.Sprint(rawSeed), err
+	}
+	rng, err := rand.NewWithWidth(rawSeed, rawWidth)
+	return rng, fmt.Sprint(rawSeed), err
+}
+
+// customRawRule reports whether --rule or --radius select something other
+// than the built-in rule
+func customRawRule() bool {
+	return rawRadius != 2 || rawRule != rand.RuleR30R2
+}
+
+// periodWarning checks whether rng's strip cycles within count bytes of
+// output (0 = unlimited) and describes the problem if so
+// The search is bounded by periodCheckGens, so longer cycles go unreported.
+func periodWarning(rng *rand.RNG, count int) string {
+	perGen := uint64(len(rng.CopyState()) * 8)
+	budget := uint64(periodCheckGens)
+	if count > 0 {
+		budget = min(budget, (uint64(count)+perGen-1)/perGen)
+	}
+	period, found := rng.Period(budget)
+	if !found || (count > 0 && period*perGen >= uint64(count)) {
+		return ""
+	}
+	return fmt.Sprintf("the strip repeats every %d generations, so output repeats every %d bytes", period, period*perGen)
+}
+
+// rngFromHex seeds an RNG with up to 32 bytes given as hex digits
+func rngFromHex(s string) (*rand.RNG, error) {
+	seed, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --seed-hex: %v", err)
+	}
+	if len(seed) > 32 {
+		return nil, fmt.Errorf("--seed-hex has %d hex digits, at most 64 allowed", len(s))
+	}
+	return rand.NewFromBytes(seed)
+}
+
+// rngFromFile seeds an RNG with the SHA-256 hash of the file at path
+func rngFromFile(path string) (*rand.RNG, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading --seed-file: %v", err)
+	}
+	seed := sha256.Sum256(data)
+	return rand.NewFromBytes(seed[:])
+}
+
+// centerBits returns n bits of the center column of seed's strip, one bit
+// per generation, packed least significant bit first
+// A final partial byte is padded with zero bits.
+func centerBits(seed uint64, n int) []byte {
+	out := make([]byte, (n+7)/8)
+	rand.NewCenterColumn(seed).Read(out)
+	if n%8 != 0 {
+		out[len(out)-1] &= 1<<(n%8) - 1
+	}
+	return out
+}
+
+// writeCenterBits writes n center-column bits of seed to w, encoded in format
+func writeCenterBits(w io.Writer, format string, seed uint64, n int) error {
+	enc, err := newEncoder(format, w)
+	if err != nil {
+		return err
+	}
+	if _, err := enc.Write(centerBits(seed, n)); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// generateBytes writes count random bytes (0 = unlimited) from rng to w, in
+// chunks to avoid huge allocations
+// It stops early when ctx is cancelled and returns the number of bytes
+// written.
+func generateBytes(ctx context.Context, w io.Writer, rng *rand.RNG, count int) (int64, error) {
+	const chunkSize = 1024 * 1024 // 1MB chunks
+	buf := make([]byte, chunkSize)
+	var written int64
+	for count == 0 || written < int64(count) {
+		if ctx.Err() != nil {
+			break
+		}
 
-			remaining -= n
+		toRead := chunkSize
+		if count != 0 && int64(count)-written < chunkSize {
+			toRead = int(int64(count) - written)
 		}
+		n, _ := rng.Read(buf[:toRead]) // never fails
+
+		m, err := w.Write(buf[:n])
+		written += int64(m)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// generateEncoded generates with the given number of workers and encodes the
+// output in format, optionally reporting progress of fixed-size output
+func generateEncoded(ctx context.Context, w io.Writer, format string, rng *rand.RNG, count, workers int, progress bool) (int64, error) {
+	enc, err := newEncoder(format, w)
+	if err != nil {
+		return 0, err
+	}
+
+	var out io.Writer = enc
+	if progress && count > 0 && !quiet {
+		cw := &countingWriter{w: enc}
+		stop := startProgress(cw, int64(count))
+		defer stop()
+		out = cw
+	}
+
+	var n int64
+	if workers > 1 {
+		n, err = generateParallel(ctx, out, rng, count, workers)
+	} else {
+		n, err = generateBytes(ctx, out, rng, count)
+	}
+	if err != nil {
+		return n, err
 	}
+	return n, enc.Close()
 }
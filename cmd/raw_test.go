@@ -0,0 +1,409 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/vrypan/r30r2/rand"
+)
+
+func TestGenerateBytesToFile(t *testing.T) {
+	const count = 3*1024*1024 + 123 // a partial final chunk
+	path := filepath.Join(t.TempDir(), "out.bin")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := generateBytes(context.Background(), f, rand.New(42), count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if n != count {
+		t.Errorf("generateBytes reported %d bytes, want %d", n, count)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := make([]byte, count)
+	rand.New(42).Read(want)
+	if !bytes.Equal(got, want) {
+		t.Errorf("file holds %d bytes that differ from the seed 42 stream", len(got))
+	}
+}
+
+// limitWriter accepts n bytes, then fails
+type limitWriter struct {
+	buf bytes.Buffer
+	n   int
+}
+
+func (w *limitWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		w.buf.Write(p[:w.n])
+		k := w.n
+		w.n = 0
+		return k, errors.New("full")
+	}
+	w.n -= len(p)
+	return w.buf.Write(p)
+}
+
+func TestGenerateBytesUnlimited(t *testing.T) {
+	w := &limitWriter{n: 2500000}
+	n, err := generateBytes(context.Background(), w, rand.New(7), 0)
+	if err == nil || n != 2500000 {
+		t.Fatalf("got %d bytes, err %v; want 2500000 bytes and an error", n, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if n, err := generateBytes(ctx, &bytes.Buffer{}, rand.New(7), 0); n != 0 || err != nil {
+		t.Errorf("cancelled: got %d bytes, err %v", n, err)
+	}
+}
+
+func TestGenerateEncodedFormats(t *testing.T) {
+	const count = 1000
+	want := make([]byte, count)
+	rand.New(99).Read(want)
+
+	cByte := regexp.MustCompile(`0x([0-9a-f]{2})`)
+	decode := map[string]func(string) ([]byte, error){
+		"raw": func(s string) ([]byte, error) { return []byte(s), nil },
+		"hex": func(s string) ([]byte, error) {
+			return hex.DecodeString(strings.TrimSpace(s))
+		},
+		"base64": func(s string) ([]byte, error) {
+			return base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+		},
+		"c": func(s string) ([]byte, error) {
+			if !strings.HasPrefix(s, "unsigned char data[] = {") || !strings.HasSuffix(s, "};\n") {
+				return nil, errors.New("not a C array definition")
+			}
+			var out []byte
+			for _, m := range cByte.FindAllStringSubmatch(s, -1) {
+				b, _ := hex.DecodeString(m[1])
+				out = append(out, b...)
+			}
+			return out, nil
+		},
+	}
+
+	for _, format := range formats {
+		var buf bytes.Buffer
+		if _, err := generateEncoded(context.Background(), &buf, format, rand.New(99), count, 1, false); err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		got, err := decode[format](buf.String())
+		if err != nil {
+			t.Errorf("%s: %v", format, err)
+			continue
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s: decoded output differs from raw output", format)
+		}
+	}
+
+	if _, err := generateEncoded(context.Background(), io.Discard, "octal", rand.New(99), count, 1, false); err == nil {
+		t.Error("unknown format accepted")
+	}
+}
+
+// chunkRecorder collects output, remembers the largest single write and
+// cancels a context after a number of writes
+type chunkRecorder struct {
+	bytes.Buffer
+	largest int
+	writes  int
+	cancel  func()
+	after   int
+}
+
+func (c *chunkRecorder) Write(p []byte) (int, error) {
+	c.largest = max(c.largest, len(p))
+	if c.writes++; c.cancel != nil && c.writes == c.after {
+		c.cancel()
+	}
+	return c.Buffer.Write(p)
+}
+
+func TestGenerateEncodedStreamsBase64(t *testing.T) {
+	const count = 2<<20 + 2 // chunk boundaries split base64 groups
+	want := make([]byte, 3<<20)
+	rand.New(4).Read(want)
+
+	var out chunkRecorder
+	if _, err := generateEncoded(context.Background(), &out, "base64", rand.New(4), count, 1, false); err != nil {
+		t.Fatal(err)
+	}
+	if out.largest > 2<<20 {
+		t.Errorf("largest write was %d bytes; output is not streamed", out.largest)
+	}
+	got, err := base64.StdEncoding.DecodeString(strings.TrimSpace(out.String()))
+	if err != nil || !bytes.Equal(got, want[:count]) {
+		t.Fatalf("fixed-size base64 does not decode to the raw stream (err %v)", err)
+	}
+
+	// An unlimited run stopped early still flushes its final partial group
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out = chunkRecorder{cancel: cancel, after: 2}
+	n, err := generateEncoded(ctx, &out, "base64", rand.New(4), 0, 1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = base64.StdEncoding.DecodeString(strings.TrimSpace(out.String()))
+	if err != nil || int64(len(got)) != n || !bytes.Equal(got, want[:n]) {
+		t.Errorf("cancelled base64 run of %d bytes does not decode to the stream (err %v)", n, err)
+	}
+}
+
+func TestQuiet(t *testing.T) {
+	var stderr bytes.Buffer
+	infoOut = &stderr
+	t.Cleanup(func() {
+		infoOut = os.Stderr
+		quiet = false
+		rawOut = ""
+	})
+
+	path := filepath.Join(t.TempDir(), "out.bin")
+	rootCmd.SetArgs([]string{"raw", "--seed", "5", "--bytes", "10", "-o", path})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if stderr.Len() == 0 {
+		t.Error("no summary on stderr without --quiet")
+	}
+
+	stderr.Reset()
+	rootCmd.SetArgs([]string{"raw", "--seed", "5", "--bytes", "10", "-o", path, "--quiet"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if stderr.Len() != 0 {
+		t.Errorf("stderr with --quiet: %q", stderr.String())
+	}
+}
+
+func TestSeedHex(t *testing.T) {
+	const lo = "0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+	outputs := make([][]byte, 2)
+	for i, s := range []string{lo + "20", lo + "21"} { // only the last byte differs
+		rng, err := rngFromHex(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		outputs[i] = make([]byte, 1024)
+		rng.Read(outputs[i])
+	}
+	if bytes.Equal(outputs[0], outputs[1]) {
+		t.Error("seeds differing in the high bytes produce the same output")
+	}
+
+	for _, bad := range []string{"xyz", "abc", lo + "2021", "00"} {
+		if _, err := rngFromHex(bad); err == nil {
+			t.Errorf("seed %q accepted", bad)
+		}
+	}
+}
+
+func TestSeedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key.txt")
+	if err := os.WriteFile(path, []byte("r30r2 seed material\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	rng, err := rngFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make([]byte, 8)
+	rng.Read(got)
+	if want := "a401835187e1d2c0"; hex.EncodeToString(got) != want {
+		t.Errorf("first bytes = %x, want %s", got, want)
+	}
+
+	if _, err := rngFromFile(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("missing file accepted")
+	}
+}
+
+func TestCenterBits(t *testing.T) {
+	const n = 100000
+	got := centerBits(7, n)
+	if !bytes.Equal(got, centerBits(7, n)) {
+		t.Fatal("center bits differ between runs with the same seed")
+	}
+	if len(got) != n/8 {
+		t.Fatalf("got %d bytes, want %d", len(got), n/8)
+	}
+
+	ones := 0
+	for _, b := range got {
+		ones += bits.OnesCount8(b)
+	}
+	if frac := float64(ones) / n; frac < 0.49 || frac > 0.51 {
+		t.Errorf("fraction of ones = %.4f, want about 0.5", frac)
+	}
+
+	// A partial final byte keeps only the requested bits
+	short := centerBits(7, 13)
+	if len(short) != 2 || short[0] != got[0] || short[1] != got[1]&0x1f {
+		t.Errorf("13 bits = %08b, want %08b %08b", short, got[0], got[1]&0x1f)
+	}
+}
+
+// resetRawFlags restores raw's flags to their defaults
+// Flags stay set between executions of rootCmd, so tests that run it with
+// different flags start each case afresh.
+func resetRawFlags() {
+	rootCmd.SetArgs(nil)
+	rootCmd.SetErr(nil)
+	rawCmd.Flags().VisitAll(func(f *pflag.Flag) {
+		f.Value.Set(f.DefValue)
+		f.Changed = false
+	})
+}
+
+func TestCenterBitsRejectsIgnoredFlags(t *testing.T) {
+	t.Cleanup(resetRawFlags)
+
+	for _, extra := range [][]string{{"--width", "128"}, {"--workers", "4"}, {"--progress"}, {"--rule", "30"}} {
+		resetRawFlags()
+		args := append([]string{"raw", "--seed", "1", "--center-bits", "64"}, extra...)
+		rootCmd.SetArgs(args)
+		rootCmd.SetErr(io.Discard)
+		if err := rootCmd.Execute(); err == nil || !strings.Contains(err.Error(), extra[0][2:]) {
+			t.Errorf("%q: err = %v, want a conflict with %s", args, err, extra[0])
+		}
+	}
+}
+
+func TestByteSeedsRejectStripFlags(t *testing.T) {
+	t.Cleanup(resetRawFlags)
+
+	for _, extra := range [][]string{{"--width", "128"}, {"--rule", "30"}, {"--radius", "1"}} {
+		for _, seed := range [][]string{{"--seed-hex", "01"}, {"--seed-file", "key.txt"}} {
+			resetRawFlags()
+			args := append(append([]string{"raw"}, seed...), extra...)
+			rootCmd.SetArgs(args)
+			rootCmd.SetErr(io.Discard)
+			err := rootCmd.Execute()
+			if err == nil || !strings.Contains(err.Error(), extra[0][2:]) || !strings.Contains(err.Error(), seed[0][2:]) {
+				t.Errorf("%q: err = %v, want a conflict between %s and %s", args, err, extra[0], seed[0])
+			}
+		}
+	}
+}
+
+func TestReportsHonorOut(t *testing.T) {
+	t.Cleanup(func() {
+		quiet = false
+		resetRawFlags()
+	})
+
+	path := filepath.Join(t.TempDir(), "digest.txt")
+	rootCmd.SetArgs([]string{"raw", "-q", "--seed", "1", "--bytes", "1000000", "--hash", "sha256", "-o", path})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	// Reference from: r30r2 --seed 1 --bytes 1000000 | sha256sum
+	const want = "1f4e43c7beba17015a7b3482d5f79c8a845b158eb41578db5085ffbe976dd4af\n"
+	if got, err := os.ReadFile(path); err != nil || string(got) != want {
+		t.Errorf("-o file = %q (err %v), want %q", got, err, want)
+	}
+
+	for _, report := range [][]string{{"--hash", "sha256"}, {"--analyze"}} {
+		for _, extra := range [][]string{{"--format", "hex"}, {"--workers", "2"}, {"--progress"}} {
+			resetRawFlags()
+			args := append(append([]string{"raw", "--seed", "1"}, report...), extra...)
+			rootCmd.SetArgs(args)
+			rootCmd.SetErr(io.Discard)
+			if err := rootCmd.Execute(); err == nil || !strings.Contains(err.Error(), extra[0][2:]) {
+				t.Errorf("%q: err = %v, want a conflict with %s", args, err, extra[0])
+			}
+		}
+	}
+}
+
+func TestPeriodWarning(t *testing.T) {
+	// Rule 204 leaves every cell unchanged: a cycle of one generation
+	fixed, err := rand.NewWithRule(42, 1, 204)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := periodWarning(fixed, 1000)
+	if !strings.Contains(msg, "every 1 generations") || !strings.Contains(msg, "every 32 bytes") {
+		t.Errorf("short cycle warning = %q", msg)
+	}
+	if msg := periodWarning(fixed, 0); msg == "" {
+		t.Error("no warning for unlimited output of a short cycle")
+	}
+	if msg := periodWarning(fixed, 32); msg != "" {
+		t.Errorf("warned about output that ends before repeating: %q", msg)
+	}
+
+	narrow, err := rand.NewWithWidth(1, 128)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg := periodWarning(narrow, 1<<20); msg != "" {
+		t.Errorf("width 128 warning = %q, want none", msg)
+	}
+}
+
+func TestRawRuleWarnsOfShortCycle(t *testing.T) {
+	var stderr bytes.Buffer
+	warnOut = &stderr
+	t.Cleanup(func() {
+		warnOut = os.Stderr
+		quiet = false
+		resetRawFlags()
+	})
+
+	path := filepath.Join(t.TempDir(), "out.bin")
+	run := func(rule string) {
+		resetRawFlags()
+		stderr.Reset()
+		rootCmd.SetArgs([]string{"raw", "-q", "--seed", "1", "--radius", "1", "--rule", rule, "--bytes", "1000", "-o", path})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("--rule %s: %v", rule, err)
+		}
+	}
+
+	// Rule 204 leaves every cell unchanged, so the output repeats at once
+	run("204")
+	if !strings.Contains(stderr.String(), "Warning: the strip repeats every 1 generations") {
+		t.Errorf("rule 204 stderr = %q, want a short cycle warning", stderr.String())
+	}
+	if data, err := os.ReadFile(path); err != nil || len(data) != 1000 || !bytes.Equal(data[:32], data[32:64]) {
+		t.Errorf("rule 204 output: %d bytes, err %v; want 1000 bytes repeating every 32", len(data), err)
+	}
+
+	run("30")
+	if stderr.Len() != 0 {
+		t.Errorf("rule 30 stderr = %q, want no warning", stderr.String())
+	}
+
+	resetRawFlags()
+	rootCmd.SetArgs([]string{"raw", "--seed", "1", "--rule", "30", "--workers", "2"})
+	rootCmd.SetErr(io.Discard)
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("--rule accepted with --workers")
+	}
+}
@@ -1,11 +1,35 @@
 package cmd
 
 import (
+	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
+// runSelfTest runs the known-answer self-test instead of generating (--selftest)
+var runSelfTest bool
+
+// quiet suppresses informational messages on stderr and the progress output
+// of compare and benchmark (--quiet)
+var quiet bool
+
+// infoOut receives informational messages; errors always go to os.Stderr
+var infoOut io.Writer = os.Stderr
+
+// warnOut receives warnings, which --quiet does not suppress
+var warnOut io.Writer = os.Stderr
+
+// infof prints an informational message unless --quiet is set
+func infof(format string, args ...any) {
+	if !quiet {
+		fmt.Fprintf(infoOut, format, args...)
+	}
+}
+
 var rootCmd = &cobra.Command{
 	Use:   "r30r2",
 	Short: "R30R2 - Random Number Generator using Rule 30 Cellular Automaton",
@@ -16,8 +40,18 @@ Uses a circular 256-bit strip with radius-2 Rule 30 evolution rules.
 
 Known for generating high-quality pseudo-randomness.
 Passes all 319 TestU01 tests including complete BigCrush suite.`,
+	Args: cobra.NoArgs,
 	// If no subcommand is provided, run the raw command by default
 	Run: func(cmd *cobra.Command, args []string) {
+		if runSelfTest {
+			if err := selfTest(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			infof("Self-test passed: seed %d, %d bytes, SHA-256 %s\n", selfTestSeed, selfTestBytes, selfTestDigest)
+			return
+		}
+
 		// If no args and no flags, run raw command with default flags
 		// This handles: r30r2 (with no arguments)
 		rawCmd.Run(rawCmd, args)
@@ -26,25 +60,77 @@ Passes all 319 TestU01 tests including complete BigCrush suite.`,
 
 // Execute runs the root command
 func Execute() error {
-	// Check if first argument is a known subcommand
-	// If not, prepend "raw" to make it the default subcommand
-	if len(os.Args) > 1 {
-		firstArg := os.Args[1]
-		// Check if it's a known subcommand or help/version flag
-		if firstArg != "raw" && firstArg != "ascii" &&
-		   firstArg != "version" && firstArg != "help" && firstArg != "completion" &&
-		   firstArg != "-h" && firstArg != "--help" {
-			// Not a subcommand, so prepend "raw"
-			os.Args = append([]string{os.Args[0], "raw"}, os.Args[1:]...)
+	rootCmd.SetArgs(dispatchArgs(os.Args[1:]))
+	return rootCmd.Execute()
+}
+
+// dispatchArgs makes raw the default subcommand: bare flags such as
+// "r30r2 --seed 1" are routed to raw, while an unknown word is left for
+// cobra to reject with usage. Root-only flags and subcommand names are
+// looked for anywhere, so "r30r2 -q --selftest" and "r30r2 -q compare"
+// still reach them.
+func dispatchArgs(args []string) []string {
+	if len(args) == 0 || !strings.HasPrefix(args[0], "-") {
+		return args
+	}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			return append([]string{"raw"}, args...)
+		case arg == "-h", arg == "--help", arg == "--selftest", strings.HasPrefix(arg, "--selftest="):
+			return args
+		case !strings.HasPrefix(arg, "-"):
+			if isSubcommand(arg) {
+				return args
+			}
+		case !strings.Contains(arg, "=") && takesValue(arg):
+			i++ // skip the value, so "--format raw" is not taken for a subcommand
 		}
 	}
+	return append([]string{"raw"}, args...)
+}
 
-	return rootCmd.Execute()
+// isSubcommand reports whether name is a subcommand of the root, or an
+// alias of one
+func isSubcommand(name string) bool {
+	if name == "help" { // added by cobra at execution time
+		return true
+	}
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// takesValue reports whether the flag arg (without "=") is a raw or
+// persistent flag that consumes the next argument
+func takesValue(arg string) bool {
+	var f *pflag.Flag
+	if name, ok := strings.CutPrefix(arg, "--"); ok {
+		if f = rawCmd.Flags().Lookup(name); f == nil {
+			f = rootCmd.PersistentFlags().Lookup(name)
+		}
+	} else if len(arg) == 2 {
+		if f = rawCmd.Flags().ShorthandLookup(arg[1:]); f == nil {
+			f = rootCmd.PersistentFlags().ShorthandLookup(arg[1:])
+		}
+	}
+	return f != nil && f.NoOptDefVal == ""
 }
 
 func init() {
+	rootCmd.Flags().BoolVar(&runSelfTest, "selftest", false, "Verify this build produces the canonical output, then exit")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Suppress informational messages and benchmark progress; results and errors are still printed")
+
 	// Add subcommands
 	rootCmd.AddCommand(rawCmd)
 	rootCmd.AddCommand(asciiCmd)
+	rootCmd.AddCommand(analyzeCmd)
+	rootCmd.AddCommand(benchmarkCmd)
+	rootCmd.AddCommand(compareCmd)
+	rootCmd.AddCommand(shuffleCmd)
 	rootCmd.AddCommand(versionCmd)
 }
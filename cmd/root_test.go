@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUnknownSubcommandPrintsUsage(t *testing.T) {
+	var out bytes.Buffer
+	rootCmd.SetArgs(dispatchArgs([]string{"frobnicate"}))
+	rootCmd.SetOut(&out)
+	rootCmd.SetErr(&out)
+	defer func() {
+		rootCmd.SetArgs(nil)
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+	}()
+
+	if err := rootCmd.Execute(); err == nil {
+		t.Fatal("unknown subcommand did not return an error")
+	}
+	if !strings.Contains(out.String(), "Usage:") {
+		t.Errorf("output does not include usage:\n%s", out.String())
+	}
+}
+
+func TestDispatchArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{nil, nil},
+		{[]string{"--seed", "1"}, []string{"raw", "--seed", "1"}},
+		{[]string{"-q"}, []string{"raw", "-q"}},
+		{[]string{"--help"}, []string{"--help"}},
+		{[]string{"--selftest"}, []string{"--selftest"}},
+		{[]string{"generate", "--bytes", "8"}, []string{"generate", "--bytes", "8"}},
+		{[]string{"frobnicate"}, []string{"frobnicate"}},
+		{[]string{"-q", "--selftest"}, []string{"-q", "--selftest"}},
+		{[]string{"--quiet", "--help"}, []string{"--quiet", "--help"}},
+		{[]string{"-q", "compare", "--json"}, []string{"-q", "compare", "--json"}},
+		{[]string{"-q", "visualize"}, []string{"-q", "visualize"}},
+		{[]string{"--format", "raw", "--bytes", "8"}, []string{"raw", "--format", "raw", "--bytes", "8"}},
+		{[]string{"-o", "ascii", "-q"}, []string{"raw", "-o", "ascii", "-q"}},
+	}
+	for _, tt := range tests {
+		if got := dispatchArgs(tt.args); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("dispatchArgs(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestPersistentFlagBeforeSelfTest(t *testing.T) {
+	rootCmd.SetArgs(dispatchArgs([]string{"-q", "--selftest"}))
+	defer func() {
+		rootCmd.SetArgs(nil)
+		quiet, runSelfTest = false, false
+	}()
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("r30r2 -q --selftest: %v", err)
+	}
+	if !quiet || !runSelfTest {
+		t.Errorf("quiet = %v, runSelfTest = %v; want both set", quiet, runSelfTest)
+	}
+}
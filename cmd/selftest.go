@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/vrypan/r30r2/rand"
+)
+
+// This file contains the known-answer self-test run by --selftest.
+
+const (
+	selfTestSeed  = 12345
+	selfTestBytes = 1 << 20
+
+	// selfTestDigest is the SHA-256 of the first selfTestBytes bytes from
+	// rand.New(selfTestSeed). It only changes if the output stream does.
+	selfTestDigest = "c1bc0309bf66d6246b7991183158edaf169d659f198cca60aaf16400caa21ce3"
+)
+
+// selfTestOutput returns the SHA-256 of the known-answer stream
+func selfTestOutput() string {
+	data := make([]byte, selfTestBytes)
+	rand.New(selfTestSeed).Read(data)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// selfTest checks that this build produces the canonical stream
+func selfTest() error {
+	if got := selfTestOutput(); got != selfTestDigest {
+		return fmt.Errorf("self-test failed: seed %d produced digest %s, want %s", selfTestSeed, got, selfTestDigest)
+	}
+	return nil
+}
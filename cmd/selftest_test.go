@@ -0,0 +1,12 @@
+package cmd
+
+import "testing"
+
+func TestSelfTestDigest(t *testing.T) {
+	if got := selfTestOutput(); got != selfTestDigest {
+		t.Errorf("known-answer digest = %s, selfTestDigest = %s", got, selfTestDigest)
+	}
+	if err := selfTest(); err != nil {
+		t.Error(err)
+	}
+}
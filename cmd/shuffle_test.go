@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/vrypan/r30r2/rand"
+)
+
+func TestShuffleLines(t *testing.T) {
+	var in strings.Builder
+	for i := range 100 {
+		fmt.Fprintf(&in, "line %d\n", i)
+	}
+	input := in.String()
+
+	shuffle := func(seed uint64, input string) string {
+		var out bytes.Buffer
+		if err := shuffleLines(&out, strings.NewReader(input), rand.New(seed)); err != nil {
+			t.Fatal(err)
+		}
+		return out.String()
+	}
+
+	a := shuffle(9, input)
+	if b := shuffle(9, input); a != b {
+		t.Error("same seed shuffled the lines differently")
+	}
+	if a == input {
+		t.Error("lines were not shuffled")
+	}
+	if shuffle(10, input) == a {
+		t.Error("different seeds gave the same order")
+	}
+
+	// Every line survives exactly once
+	got, want := strings.Split(a, "\n"), strings.Split(input, "\n")
+	slices.Sort(got)
+	slices.Sort(want)
+	if !slices.Equal(got, want) {
+		t.Error("shuffled output does not hold the same lines")
+	}
+
+	// A missing final newline is added; empty input stays empty
+	if got := shuffle(1, "only"); got != "only\n" {
+		t.Errorf("single line without newline = %q", got)
+	}
+	if got := shuffle(1, ""); got != "" {
+		t.Errorf("empty input = %q", got)
+	}
+}
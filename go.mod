@@ -2,9 +2,11 @@ module github.com/vrypan/r30r2
 
 go 1.25.5
 
-require github.com/spf13/cobra v1.10.2
-
 require (
-	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/spf13/pflag v1.0.10 // indirect
+	github.com/spf13/cobra v1.10.2
+	github.com/spf13/pflag v1.0.10
+	golang.org/x/crypto v0.55.0
+	golang.org/x/sys v0.47.0
 )
+
+require github.com/inconshreveable/mousetrap v1.1.0 // indirect
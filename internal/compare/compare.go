@@ -0,0 +1,169 @@
+// Package compare benchmarks R30R2 against other random number generators
+// for the r30r2 compare and benchmark subcommands.
+package compare
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/vrypan/r30r2/bench"
+)
+
+// DefaultSizes are the buffer sizes measured by default
+var DefaultSizes = []int{
+	1024,              // 1 KB
+	10 * 1024,         // 10 KB
+	100 * 1024,        // 100 KB
+	1024 * 1024,       // 1 MB
+	100 * 1024 * 1024, // 100 MB
+}
+
+// ParseSizes parses a comma-separated list of buffer sizes in bytes
+func ParseSizes(s string) ([]int, error) {
+	var sizes []int
+	for _, field := range strings.Split(s, ",") {
+		size, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || size < 1 {
+			return nil, fmt.Errorf("invalid size %q (want a positive byte count)", field)
+		}
+		sizes = append(sizes, size)
+	}
+	return sizes, nil
+}
+
+// DefaultIterations returns how many buffers of size to read by default,
+// about 100 MB in total but at least 10 buffers
+func DefaultIterations(size int) int {
+	return max(100*1024*1024/size, 10)
+}
+
+// BenchResult holds benchmark results
+type BenchResult = bench.BenchResult
+
+// Run reads iterations buffers of size bytes from a fresh reader of src
+func Run(src Source, size, iterations int) (BenchResult, error) {
+	result := bench.RunReadBenchmark(src.Name, src.New(), size, iterations)
+	if result.Err != nil {
+		return BenchResult{}, fmt.Errorf("%s: %v", src.Name, result.Err)
+	}
+	return result, nil
+}
+
+// RunAll measures every source at every size, in that order, printing a
+// progress line per measurement to log
+func RunAll(sources []Source, sizes []int, iterations func(size int) int, log io.Writer) ([]BenchResult, error) {
+	var results []BenchResult
+	for _, size := range sizes {
+		iters := iterations(size)
+		fmt.Fprintf(log, "Testing with %s buffers (%d iterations)...\n", FormatSize(size), iters)
+
+		for _, src := range sources {
+			result, err := Run(src, size, iters)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, result)
+			fmt.Fprintf(log, "  ✓ %-14s %7.2f MB/s\n", src.Name+":", result.Throughput)
+		}
+		fmt.Fprintln(log)
+	}
+	return results, nil
+}
+
+// FormatSize formats bytes as MB or KB when it is a whole number of them
+func FormatSize(bytes int) string {
+	switch {
+	case bytes%(1024*1024) == 0:
+		return fmt.Sprintf("%d MB", bytes/(1024*1024))
+	case bytes%1024 == 0:
+		return fmt.Sprintf("%d KB", bytes/1024)
+	}
+	return fmt.Sprintf("%d B", bytes)
+}
+
+// WriteJSON writes results as a JSON array
+func WriteJSON(w io.Writer, results []BenchResult) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(results)
+}
+
+// WriteCSV writes results as CSV, one row per source and size
+// Numbers use strconv formatting, so the decimal separator is always '.'.
+func WriteCSV(w io.Writer, results []BenchResult) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"rng", "size", "throughput_mbps", "entropy", "chisquare"})
+	for _, r := range results {
+		cw.Write([]string{
+			r.Name,
+			strconv.Itoa(r.Size),
+			strconv.FormatFloat(r.Throughput, 'f', 2, 64),
+			strconv.FormatFloat(r.Entropy, 'f', 6, 64),
+			strconv.FormatFloat(r.ChiSquare, 'f', 2, 64),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteTables writes the human-readable summary tables of results, one row
+// per source and one column per size
+func WriteTables(w io.Writer, results []BenchResult) {
+	var names []string
+	var sizes []int
+	byKey := make(map[string]map[int]BenchResult)
+	for _, r := range results {
+		if byKey[r.Name] == nil {
+			byKey[r.Name] = make(map[int]BenchResult)
+			names = append(names, r.Name)
+		}
+		if !slices.Contains(sizes, r.Size) {
+			sizes = append(sizes, r.Size)
+		}
+		byKey[r.Name][r.Size] = r
+	}
+
+	tables := []struct {
+		title string
+		cell  func(BenchResult) string
+	}{
+		{"Throughput (MB/s)", func(r BenchResult) string { return fmt.Sprintf("%8.0f MB/s", r.Throughput) }},
+		{"Entropy (bits/byte, max 8)", func(r BenchResult) string { return fmt.Sprintf("%12.4f", r.Entropy) }},
+		{"Chi-square (255 expected)", func(r BenchResult) string { return fmt.Sprintf("%12.1f", r.ChiSquare) }},
+	}
+	for _, table := range tables {
+		fmt.Fprintln(w, "═══════════════════════════════════════════════════════════")
+		fmt.Fprintf(w, "  Summary Table: %s\n", table.title)
+		fmt.Fprintln(w, "═══════════════════════════════════════════════════════════")
+		fmt.Fprintln(w)
+
+		// Table header
+		fmt.Fprintf(w, "%-15s", "RNG")
+		for _, size := range sizes {
+			fmt.Fprintf(w, " │ %-12s ", FormatSize(size))
+		}
+		fmt.Fprintln(w)
+
+		// Separator
+		fmt.Fprint(w, "───────────────")
+		for range sizes {
+			fmt.Fprint(w, "─┼──────────────")
+		}
+		fmt.Fprintln(w)
+
+		// Table rows
+		for _, name := range names {
+			fmt.Fprintf(w, "%-15s", name)
+			for _, size := range sizes {
+				fmt.Fprintf(w, " │ %s", table.cell(byKey[name][size]))
+			}
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintln(w)
+	}
+}
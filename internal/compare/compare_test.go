@@ -0,0 +1,156 @@
+package compare
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestWriteJSON(t *testing.T) {
+	sources := DefaultSources()
+	results, err := RunAll(sources, []int{1024, 4096}, func(int) int { return 4 }, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 2*len(sources) {
+		t.Fatalf("got %d results, want %d", len(decoded), 2*len(sources))
+	}
+
+	names := make(map[string]bool)
+	for _, r := range decoded {
+		for _, key := range []string{"name", "size", "duration", "throughput", "entropy", "chiSquare"} {
+			if _, ok := r[key]; !ok {
+				t.Errorf("result %v has no %q key", r, key)
+			}
+		}
+		names[r["name"].(string)] = true
+	}
+	for _, src := range sources {
+		if !names[src.Name] {
+			t.Errorf("no results for %s", src.Name)
+		}
+	}
+}
+
+func TestWriteTables(t *testing.T) {
+	results, err := RunAll(DefaultSources(), []int{1024, 2 * 1024 * 1024}, func(int) int { return 1 }, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	WriteTables(&buf, results)
+	for _, want := range []string{"Throughput", "Entropy", "Chi-square", "R30R2RNG", "crypto/rand"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("tables do not mention %q", want)
+		}
+	}
+	for _, size := range []string{"1 KB", "2 MB"} {
+		if n := strings.Count(buf.String(), fmt.Sprintf("│ %-12s", size)); n != 3 {
+			t.Errorf("size %s appears in %d tables, want 3", size, n)
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	sources := DefaultSources()
+	results, err := RunAll(sources, []int{1024, 4096, 8192}, func(int) int { return 2 }, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header := []string{"rng", "size", "throughput_mbps", "entropy", "chisquare"}
+	if !slices.Equal(rows[0], header) {
+		t.Errorf("header = %v, want %v", rows[0], header)
+	}
+	if len(rows)-1 != 3*len(sources) {
+		t.Errorf("got %d rows, want %d", len(rows)-1, 3*len(sources))
+	}
+	for _, row := range rows[1:] {
+		for _, field := range row[2:] {
+			if _, err := strconv.ParseFloat(field, 64); err != nil {
+				t.Errorf("row %v: %v", row, err)
+			}
+		}
+	}
+}
+
+func TestCustomSizes(t *testing.T) {
+	sizes, err := ParseSizes("2048,4096")
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := RunAll(DefaultSources(), sizes, func(int) int { return 3 }, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[int]int)
+	for _, r := range results {
+		got[r.Size]++
+	}
+	n := len(DefaultSources())
+	if len(got) != 2 || got[2048] != n || got[4096] != n {
+		t.Errorf("results per size = %v, want %d each for 2048 and 4096", got, n)
+	}
+
+	for _, bad := range []string{"", "1024,", "abc", "0", "-5"} {
+		if _, err := ParseSizes(bad); err == nil {
+			t.Errorf("ParseSizes(%q) accepted", bad)
+		}
+	}
+}
+
+func TestCompetitors(t *testing.T) {
+	results, err := RunAll(DefaultSources(), []int{64 * 1024}, func(int) int { return 16 }, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := make(map[string]BenchResult)
+	for _, r := range results {
+		found[r.Name] = r
+	}
+	for _, name := range []string{"xorshift128+", "PCG"} {
+		r, ok := found[name]
+		if !ok {
+			t.Errorf("%s missing from results", name)
+			continue
+		}
+		if r.Throughput <= 0 || r.Entropy < 7.9 || r.ChiSquare <= 0 {
+			t.Errorf("%s: implausible result %+v", name, r)
+		}
+	}
+}
+
+func TestXorshift128Plus(t *testing.T) {
+	// First outputs of the reference C code for s = {1, 2}, worked by hand
+	x := &xorshift128PlusReader{s0: 1, s1: 2}
+	for i, want := range []uint64{0x3, 0x800025, 0x2040083} {
+		if got := x.next(); got != want {
+			t.Errorf("output %d = %#x, want %#x", i, got, want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package compare
+
+import (
+	cryptorand "crypto/rand"
+	"encoding/binary"
+	"io"
+	mathrand "math/rand"
+	mathrandv2 "math/rand/v2"
+
+	"github.com/vrypan/r30r2/rand"
+)
+
+// This file contains the RNGs under comparison, wrapped as io.Readers.
+
+// Source is a named RNG under test
+// New returns a freshly seeded reader, so every measurement starts from the
+// same state.
+type Source struct {
+	Name string
+	New  func() io.Reader
+}
+
+// DefaultSources returns the RNGs compared by default, all seeded with 12345
+func DefaultSources() []Source {
+	return []Source{
+		{"R30R2RNG", func() io.Reader { return rand.New(12345) }},
+		{"math/rand", func() io.Reader { return newMathRandReader(12345) }},
+		{"PCG", func() io.Reader { return newMathRandV2Reader(12345) }},
+		{"xorshift128+", func() io.Reader { return newXorshift128PlusReader(12345) }},
+		{"crypto/rand", func() io.Reader { return cryptorand.Reader }},
+	}
+}
+
+// mathRandReader wraps math/rand to implement io.Reader
+type mathRandReader struct {
+	rng *mathrand.Rand
+}
+
+func (m *mathRandReader) Read(p []byte) (n int, err error) {
+	return m.rng.Read(p)
+}
+
+func newMathRandReader(seed int64) io.Reader {
+	return &mathRandReader{
+		rng: mathrand.New(mathrand.NewSource(seed)),
+	}
+}
+
+// mathRandV2Reader wraps a math/rand/v2 PCG generator to implement io.Reader
+type mathRandV2Reader struct {
+	rng *mathrandv2.Rand
+}
+
+func (m *mathRandV2Reader) Read(p []byte) (n int, err error) {
+	// math/rand/v2 doesn't have Read(), so implement it manually
+	for i := 0; i < len(p); i += 8 {
+		val := m.rng.Uint64()
+		for j := 0; j < 8 && i+j < len(p); j++ {
+			p[i+j] = byte(val)
+			val >>= 8
+		}
+	}
+	return len(p), nil
+}
+
+func newMathRandV2Reader(seed uint64) io.Reader {
+	return &mathRandV2Reader{
+		rng: mathrandv2.New(mathrandv2.NewPCG(seed, seed)),
+	}
+}
+
+// xorshift128PlusReader is Vigna's xorshift128+ generator as an io.Reader
+type xorshift128PlusReader struct {
+	s0, s1 uint64
+}
+
+func (x *xorshift128PlusReader) next() uint64 {
+	s1, s0 := x.s0, x.s1
+	result := s0 + s1
+	x.s0 = s0
+	s1 ^= s1 << 23
+	x.s1 = s1 ^ s0 ^ (s1 >> 18) ^ (s0 >> 5)
+	return result
+}
+
+func (x *xorshift128PlusReader) Read(p []byte) (n int, err error) {
+	i := 0
+	for ; i+8 <= len(p); i += 8 {
+		binary.LittleEndian.PutUint64(p[i:], x.next())
+	}
+	if i < len(p) {
+		var tail [8]byte
+		binary.LittleEndian.PutUint64(tail[:], x.next())
+		copy(p[i:], tail[:])
+	}
+	return len(p), nil
+}
+
+// newXorshift128PlusReader seeds xorshift128+ by expanding seed with
+// SplitMix64, as its authors recommend
+func newXorshift128PlusReader(seed uint64) io.Reader {
+	return &xorshift128PlusReader{s0: splitMix64(&seed), s1: splitMix64(&seed)}
+}
+
+// splitMix64 advances a SplitMix64 state and returns its next output
+func splitMix64(state *uint64) uint64 {
+	*state += 0x9e3779b97f4a7c15
+	z := *state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
@@ -0,0 +1,16 @@
+package compare
+
+import "fmt"
+
+// This file contains helpers for the Uint64() latency benchmark, which is
+// timed by bench.RunUint64Benchmark.
+
+// FormatCalls formats call counts
+func FormatCalls(calls int) string {
+	if calls >= 1000000 {
+		return fmt.Sprintf("%dM calls", calls/1000000)
+	} else if calls >= 1000 {
+		return fmt.Sprintf("%dk calls", calls/1000)
+	}
+	return fmt.Sprintf("%d calls", calls)
+}
@@ -1,16 +1,57 @@
 package rand
 
-import "math"
+import (
+	"math"
+	"math/bits"
+	mathrand "math/rand"
+)
 
 // This file contains math/rand compatibility methods that build on top of
 // the core Uint64() and Read() methods defined in r30r2.go.
 
+// RNG can be used directly as a math/rand source:
+//
+//	mr := mathrand.New(rand.New(12345))
+var (
+	_ mathrand.Source   = (*RNG)(nil)
+	_ mathrand.Source64 = (*RNG)(nil)
+)
+
+// Seed re-initializes the strip from seed, exactly as New does
+// Implements math/rand.Source
+func (r *RNG) Seed(seed int64) {
+	r.init(uint64(seed))
+}
+
 // Uint32 returns a random uint32
+// It takes the next four bytes of the byte stream, so each underlying Uint64
+// serves two calls: the low 32 bits first, then the high 32 bits. Like
+// ReadByte, it continues exactly where Read left off.
 func (r *RNG) Uint32() uint32 {
-	return uint32(r.Uint64())
+	switch {
+	case r.nbuf >= 4:
+		v := uint32(r.buf)
+		r.buf >>= 32
+		r.nbuf -= 4
+		return v
+	case r.nbuf == 0:
+		w := r.word()
+		r.buf = w >> 32
+		r.nbuf = 4
+		return uint32(w)
+	}
+
+	// The four bytes straddle two words
+	var v uint32
+	for i := range 4 {
+		b, _ := r.ReadByte()
+		v |= uint32(b) << (8 * i)
+	}
+	return v
 }
 
 // Int63 returns a non-negative random int64 (0 to 2^63-1)
+// Implements math/rand.Source
 func (r *RNG) Int63() int64 {
 	return int64(r.Uint64() & 0x7FFFFFFFFFFFFFFF)
 }
@@ -27,6 +68,7 @@ func (r *RNG) Int() int {
 }
 
 // Int63n returns a random int64 in [0, n)
+// Uses rejection sampling so every value is equally likely (no modulo bias)
 // Panics if n <= 0
 func (r *RNG) Int63n(n int64) int64 {
 	if n <= 0 {
@@ -44,6 +86,7 @@ func (r *RNG) Int63n(n int64) int64 {
 }
 
 // Int31n returns a random int32 in [0, n)
+// Uses rejection sampling so every value is equally likely (no modulo bias)
 // Panics if n <= 0
 func (r *RNG) Int31n(n int32) int32 {
 	if n <= 0 {
@@ -60,7 +103,7 @@ func (r *RNG) Int31n(n int32) int32 {
 	return v % n
 }
 
-// Intn returns a random int in [0, n)
+// Intn returns a random int in [0, n), without modulo bias
 // Panics if n <= 0
 func (r *RNG) Intn(n int) int {
 	if n <= 0 {
@@ -72,20 +115,72 @@ func (r *RNG) Intn(n int) int {
 	return int(r.Int63n(int64(n)))
 }
 
+// Uint64N returns a random uint64 in [0, n), without modulo bias, like
+// math/rand/v2's Uint64N
+// Uses Lemire's multiply-shift reduction, which only needs a second draw
+// with probability below n/2^64.
+// Panics if n == 0
+func (r *RNG) Uint64N(n uint64) uint64 {
+	if n == 0 {
+		panic("invalid argument to Uint64N")
+	}
+	if n&(n-1) == 0 { // n is power of two
+		return r.Uint64() & (n - 1)
+	}
+	hi, lo := bits.Mul64(r.Uint64(), n)
+	if lo < n {
+		thresh := -n % n // 2^64 mod n
+		for lo < thresh {
+			hi, lo = bits.Mul64(r.Uint64(), n)
+		}
+	}
+	return hi
+}
+
+// Perm returns a random permutation of the integers [0, n)
+// Uses Fisher-Yates driven by Intn, so the result depends only on the seed
+func (r *RNG) Perm(n int) []int {
+	m := make([]int, n)
+	for i := 0; i < n; i++ {
+		j := r.Intn(i + 1)
+		m[i] = m[j]
+		m[j] = i
+	}
+	return m
+}
+
+// Shuffle pseudo-randomizes the order of n elements using Fisher-Yates
+// swap swaps the elements with indexes i and j
+// Panics if n < 0
+func (r *RNG) Shuffle(n int, swap func(i, j int)) {
+	if n < 0 {
+		panic("invalid argument to Shuffle")
+	}
+	for i := n - 1; i > 0; i-- {
+		j := r.Intn(i + 1)
+		swap(i, j)
+	}
+}
+
 // Float64 returns a random float64 in [0.0, 1.0)
+// The result is never exactly 1.0
 func (r *RNG) Float64() float64 {
-	// Use 53 bits of precision (same as math/rand)
-	return float64(r.Int63()>>11) / (1 << 52)
+	// Use 53 bits of precision (the full float64 mantissa)
+	return float64(r.Uint64()>>11) * (1.0 / (1 << 53))
 }
 
 // Float32 returns a random float32 in [0.0, 1.0)
+// The result is never exactly 1.0
 func (r *RNG) Float32() float32 {
-	// Use 24 bits of precision
-	return float32(r.Int31()>>7) / (1 << 24)
+	// Use 24 bits of precision (the full float32 mantissa)
+	return float32(r.Uint32()>>8) * (1.0 / (1 << 24))
 }
 
 // NormFloat64 returns a normally distributed float64 with mean 0 and stddev 1
-// Uses the Box-Muller transform
+// Uses the Marsaglia polar method on uniform draws from the strip
+// For other distributions scale and shift the result:
+//
+//	sample := r.NormFloat64()*stddev + mean
 func (r *RNG) NormFloat64() float64 {
 	for {
 		u := 2*r.Float64() - 1
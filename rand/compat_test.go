@@ -0,0 +1,250 @@
+package rand
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	mathrand "math/rand"
+	"testing"
+)
+
+func TestInt63Deterministic(t *testing.T) {
+	a := New(12345)
+	b := New(12345)
+	for i := 0; i < 10000; i++ {
+		if x, y := a.Int63(), b.Int63(); x != y {
+			t.Fatalf("Int63 #%d: %d != %d", i, x, y)
+		}
+	}
+}
+
+func TestSeedMatchesNew(t *testing.T) {
+	r := New(1)
+	r.Uint64() // move away from the initial position
+	r.Seed(12345)
+
+	ref := New(12345)
+	for i := 0; i < 1000; i++ {
+		if x, y := r.Int63(), ref.Int63(); x != y {
+			t.Fatalf("Int63 #%d after Seed: %d != %d", i, x, y)
+		}
+	}
+}
+
+func TestMathRandSource(t *testing.T) {
+	a := mathrand.New(New(12345))
+	b := mathrand.New(New(12345))
+	for i := 0; i < 1000; i++ {
+		if x, y := a.Intn(1000), b.Intn(1000); x != y {
+			t.Fatalf("Intn #%d: %d != %d", i, x, y)
+		}
+	}
+
+	pa, pb := a.Perm(52), b.Perm(52)
+	for i := range pa {
+		if pa[i] != pb[i] {
+			t.Fatalf("Perm differs at %d: %v != %v", i, pa, pb)
+		}
+	}
+}
+
+func TestIntnUniform(t *testing.T) {
+	const (
+		n     = 6
+		draws = 3000000
+	)
+	r := New(42)
+	var counts [n]int
+	for i := 0; i < draws; i++ {
+		counts[r.Intn(n)]++
+	}
+
+	// Chi-square with 5 degrees of freedom; 20.52 is the p=0.001 critical value
+	expected := float64(draws) / n
+	chi := 0.0
+	for _, c := range counts {
+		d := float64(c) - expected
+		chi += d * d / expected
+	}
+	if chi > 20.52 {
+		t.Errorf("Intn(%d) chi-square = %.2f, counts %v", n, chi, counts)
+	}
+}
+
+func TestUint64NUniform(t *testing.T) {
+	const (
+		n     = 7
+		draws = 3500000
+	)
+	r := New(99)
+	var counts [n]int
+	for i := 0; i < draws; i++ {
+		counts[r.Uint64N(n)]++
+	}
+
+	// Chi-square with 6 degrees of freedom; 22.46 is the p=0.001 critical value
+	expected := float64(draws) / n
+	chi := 0.0
+	for _, c := range counts {
+		d := float64(c) - expected
+		chi += d * d / expected
+	}
+	if chi > 22.46 {
+		t.Errorf("Uint64N(%d) chi-square = %.2f, counts %v", n, chi, counts)
+	}
+
+	for _, n := range []uint64{1, 2, 1 << 40, 1<<63 + 1, math.MaxUint64} {
+		for i := 0; i < 1000; i++ {
+			if v := r.Uint64N(n); v >= n {
+				t.Fatalf("Uint64N(%d) = %d", n, v)
+			}
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Uint64N(0) did not panic")
+		}
+	}()
+	r.Uint64N(0)
+}
+
+func TestInt63nRange(t *testing.T) {
+	r := New(7)
+	for _, n := range []int64{1, 2, 3, 1000, 1<<62 + 1} {
+		for i := 0; i < 1000; i++ {
+			if v := r.Int63n(n); v < 0 || v >= n {
+				t.Fatalf("Int63n(%d) = %d", n, v)
+			}
+		}
+	}
+}
+
+func TestIntnPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Intn(0) did not panic")
+		}
+	}()
+	New(1).Intn(0)
+}
+
+func TestFloatMoments(t *testing.T) {
+	const n = 1000000
+	r := New(2024)
+
+	var sum64, sq64, sum32, sq32 float64
+	for i := 0; i < n; i++ {
+		f := r.Float64()
+		if f < 0 || f >= 1 {
+			t.Fatalf("Float64() = %v out of [0, 1)", f)
+		}
+		sum64 += f
+		sq64 += f * f
+
+		g := float64(r.Float32())
+		if g < 0 || g >= 1 {
+			t.Fatalf("Float32() = %v out of [0, 1)", g)
+		}
+		sum32 += g
+		sq32 += g * g
+	}
+
+	check := func(name string, sum, sq float64) {
+		mean := sum / n
+		variance := sq/n - mean*mean
+		if math.Abs(mean-0.5) > 0.002 {
+			t.Errorf("%s mean = %.5f, want 0.5", name, mean)
+		}
+		if math.Abs(variance-1.0/12) > 0.001 {
+			t.Errorf("%s variance = %.5f, want %.5f", name, variance, 1.0/12)
+		}
+	}
+	check("Float64", sum64, sq64)
+	check("Float32", sum32, sq32)
+}
+
+func TestNormFloat64Moments(t *testing.T) {
+	const n = 300000
+	r := New(99)
+
+	var sum, sq float64
+	for i := 0; i < n; i++ {
+		x := r.NormFloat64()
+		sum += x
+		sq += x * x
+	}
+	mean := sum / n
+	stddev := math.Sqrt(sq/n - mean*mean)
+	if math.Abs(mean) > 0.01 {
+		t.Errorf("mean = %.5f, want 0", mean)
+	}
+	if math.Abs(stddev-1) > 0.01 {
+		t.Errorf("stddev = %.5f, want 1", stddev)
+	}
+}
+
+func TestPermIsPermutation(t *testing.T) {
+	r := New(5)
+	for _, n := range []int{0, 1, 2, 10, 1000} {
+		p := r.Perm(n)
+		if len(p) != n {
+			t.Fatalf("Perm(%d) has length %d", n, len(p))
+		}
+		seen := make([]bool, n)
+		for _, v := range p {
+			if v < 0 || v >= n || seen[v] {
+				t.Fatalf("Perm(%d) is not a permutation: %v", n, p)
+			}
+			seen[v] = true
+		}
+	}
+}
+
+func TestShuffleDeterministic(t *testing.T) {
+	deck := func(seed uint64) []int {
+		d := make([]int, 52)
+		for i := range d {
+			d[i] = i
+		}
+		New(seed).Shuffle(len(d), func(i, j int) { d[i], d[j] = d[j], d[i] })
+		return d
+	}
+
+	a, b := deck(31337), deck(31337)
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("same seed produced different shuffles: %v vs %v", a, b)
+		}
+	}
+
+	pa, pb := New(31337).Perm(52), New(31337).Perm(52)
+	for i := range pa {
+		if pa[i] != pb[i] {
+			t.Fatalf("same seed produced different permutations: %v vs %v", pa, pb)
+		}
+	}
+}
+
+func TestUint32Halves(t *testing.T) {
+	a, b := New(4242), New(4242)
+	for range 100 {
+		lo, hi := a.Uint32(), a.Uint32()
+		if want := b.Uint64(); uint64(hi)<<32|uint64(lo) != want {
+			t.Fatalf("Uint32 pair %#x, %#x does not rebuild Uint64 %#x", lo, hi, want)
+		}
+	}
+
+	// Uint32 continues the byte stream, even across word boundaries
+	want := make([]byte, 64)
+	New(4242).Read(want)
+	r := New(4242)
+	got := make([]byte, 3, 64)
+	r.Read(got)
+	for len(got) < 63 {
+		got = binary.LittleEndian.AppendUint32(got, r.Uint32())
+	}
+	if !bytes.Equal(got, want[:63]) {
+		t.Error("Uint32 after an odd Read does not continue the byte stream")
+	}
+}
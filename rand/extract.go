@@ -0,0 +1,159 @@
+package rand
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// This file contains alternative output modes that emit raw strip cells
+// instead of the mixed strip words produced by default.
+
+// outputMode selects how output words are extracted from the strip
+type outputMode uint8
+
+const (
+	modeMixed  outputMode = iota // every strip word, through mix() (default)
+	modeSample                   // evenly spaced raw cells of each generation
+	modeMask                     // raw cells chosen by a mask
+)
+
+// valid reports whether m is a known output mode
+func (m outputMode) valid() bool {
+	return m <= modeMask
+}
+
+// NewCenterColumn creates an RNG in Wolfram's classic center-column mode
+// Each output bit is the unmixed center cell of one generation, so every
+// byte takes 8 generations, least significant bit first. That is 256 times
+// fewer output bits per step than the full-strip mode, and correspondingly
+// slower; it exists to study the historically analysed randomness source.
+func NewCenterColumn(seed uint64) *RNG {
+	rng, _ := NewWithSampling(seed, 1)
+	return rng
+}
+
+// NewWithSampling creates an RNG that outputs only keepBits raw cells per
+// generation, evenly spaced around the strip (e.g. 64 keeps every 4th cell)
+// Discarding the cells in between breaks correlation between neighbouring
+// cells. Cells are packed in strip order, first cell in the least significant
+// bit, and are not mixed. keepBits must divide 256; keepBits 1 is the center
+// column.
+func NewWithSampling(seed uint64, keepBits int) (*RNG, error) {
+	if keepBits < 1 || DefaultWidth%keepBits != 0 {
+		return nil, fmt.Errorf("rand: keepBits %d does not divide %d", keepBits, DefaultWidth)
+	}
+	rng := New(seed)
+	rng.mode = modeSample
+	rng.keep = keepBits
+	return rng, nil
+}
+
+// NewWithMask creates an RNG that outputs the raw cells selected by mask from
+// every generation
+// Cell c is selected by bit 63-c%64 of mask[c/64], the layout of CopyState.
+// Selected cells are packed in strip order, first cell in the least
+// significant bit, and are not mixed: a mask of only cell 128 is the center
+// column and a full mask emits the whole raw strip. At least one bit must be
+// set.
+func NewWithMask(seed uint64, mask [4]uint64) (*RNG, error) {
+	cells := maskCells(mask[:])
+	if len(cells) == 0 {
+		return nil, errors.New("rand: mask selects no cells")
+	}
+	rng := New(seed)
+	rng.mode = modeMask
+	rng.keep = len(cells)
+	rng.cells = cells
+	return rng, nil
+}
+
+// maskCells returns the cells selected by mask, in strip order
+func maskCells(mask []uint64) []int {
+	var cells []int
+	for c := range 64 * len(mask) {
+		if mask[c/64]>>(63-c%64)&1 == 1 {
+			cells = append(cells, c)
+		}
+	}
+	return cells
+}
+
+// NewWithBitOrder creates an RNG whose output bytes have the bit order of
+// New's reversed when msbFirst is set
+// Bytes are otherwise packed least significant bit first: in the raw-cell
+// modes the first cell lands in bit 0 of each byte. msbFirst puts it in bit 7
+// instead, for tools that expect that order. msbFirst false is identical to
+// New.
+func NewWithBitOrder(seed uint64, msbFirst bool) *RNG {
+	rng := New(seed)
+	rng.msbFirst = msbFirst
+	return rng
+}
+
+// ReadCellOrder fills p with the raw strip of successive generations, packed
+// in the order the visualizer draws cells
+// Each generation takes 32 bytes and cell c is bit 7-c%8 of byte c/8, so the
+// bytes are the CopyState words in big-endian order and the first 32 bytes
+// of a fresh RNG are the generation StepGeneration would return first. It
+// reads the same strip words the default Read mixes and shares its position,
+// including bytes left over from a partial read; it ignores the output mode
+// and bit order. Like Read it always returns len(p), nil.
+func (r *RNG) ReadCellOrder(p []byte) (n int, err error) {
+	for n < len(p) {
+		if r.nbuf == 0 {
+			if r.pos >= len(r.state) {
+				r.step()
+				r.pos = 0
+			}
+			r.buf = bits.ReverseBytes64(r.state[r.pos])
+			r.nbuf = 8
+			r.pos++
+		}
+		p[n] = byte(r.buf)
+		r.buf >>= 8
+		r.nbuf--
+		n++
+	}
+	return n, nil
+}
+
+// extract produces the next output word for the non-default output modes
+// and bit order
+func (r *RNG) extract() uint64 {
+	var v uint64
+	switch r.mode {
+	case modeMixed:
+		v = r.mixedWord()
+	case modeSample, modeMask:
+		v = r.sampleWord()
+	default:
+		panic("rand: unknown output mode")
+	}
+	if r.msbFirst {
+		v = bits.ReverseBytes64(bits.Reverse64(v))
+	}
+	return v
+}
+
+// sampleWord packs the next 64 sampled cells, stepping the strip as needed
+// Sample i of a generation is cell i*stride + stride/2, so a single sample
+// is the center cell, or the i-th masked cell in modeMask.
+func (r *RNG) sampleWord() uint64 {
+	stride := len(r.state) * 64 / r.keep
+
+	var v uint64
+	for i := 0; i < 64; i++ {
+		if r.left == 0 {
+			r.step()
+			r.left = r.keep
+		}
+		c := (r.keep-r.left)*stride + stride/2
+		if r.cells != nil {
+			c = r.cells[r.keep-r.left]
+		}
+		r.left--
+		v |= (r.state[c/64] >> (63 - c%64) & 1) << i
+	}
+	return v
+}
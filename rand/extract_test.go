@@ -0,0 +1,266 @@
+package rand
+
+import (
+	"bytes"
+	"math"
+	"math/bits"
+	"testing"
+)
+
+func TestCenterColumnBits(t *testing.T) {
+	r := NewCenterColumn(12345)
+	ref := New(12345)
+
+	out := make([]byte, 16)
+	r.Read(out)
+	for i := 0; i < len(out)*8; i++ {
+		ref.step()
+		want := cell(ref, 128)
+		got := out[i/8]>>(i%8)&1 == 1
+		if got != want {
+			t.Fatalf("bit %d: got %v, want center cell %v", i, got, want)
+		}
+	}
+}
+
+func TestCenterColumnDeterministicAndBalanced(t *testing.T) {
+	a, b := make([]byte, 64<<10), make([]byte, 64<<10)
+	NewCenterColumn(2026).Read(a)
+	NewCenterColumn(2026).Read(b)
+	if !bytes.Equal(a, b) {
+		t.Fatal("center column stream is not deterministic")
+	}
+
+	ones := 0
+	for _, v := range a {
+		ones += bits.OnesCount8(v)
+	}
+	frac := float64(ones) / float64(len(a)*8)
+	if frac < 0.49 || frac > 0.51 {
+		t.Errorf("center column ones fraction = %.4f", frac)
+	}
+}
+
+func TestMarshalCenterColumn(t *testing.T) {
+	orig := NewCenterColumn(5)
+	orig.Read(make([]byte, 3))
+
+	data, _ := orig.MarshalBinary()
+	restored := &RNG{}
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+
+	want, got := make([]byte, 64), make([]byte, 64)
+	orig.Read(want)
+	restored.Read(got)
+	if !bytes.Equal(got, want) {
+		t.Error("center column mode lost in snapshot round trip")
+	}
+}
+
+// lag1 returns the lag-1 autocorrelation of the bit stream in data,
+// taking bits least significant first
+func lag1(data []byte) float64 {
+	var s, ss, sp float64
+	prev := float64(data[0] & 1)
+	n := float64(len(data)*8 - 1)
+	for i := 1; i < len(data)*8; i++ {
+		x := float64(data[i/8] >> (i % 8) & 1)
+		s += prev
+		ss += prev * prev
+		sp += prev * x
+		prev = x
+	}
+	m := s / n
+	return (sp/n - m*m) / (ss/n - m*m)
+}
+
+func TestSamplingReducesCorrelation(t *testing.T) {
+	// Neighbouring cells of classic Rule 30 are measurably correlated
+	stream := func(keep int) []byte {
+		r, err := NewWithSampling(12345, keep)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.radius, r.rule = 1, 30
+		data := make([]byte, 256<<10)
+		r.Read(data)
+		return data
+	}
+
+	full := math.Abs(lag1(stream(256)))
+	decimated := math.Abs(lag1(stream(64)))
+	t.Logf("lag-1 autocorrelation: full %.5f, every 4th cell %.5f", full, decimated)
+	if decimated >= full {
+		t.Errorf("decimation did not lower the correlation (%.5f >= %.5f)", decimated, full)
+	}
+}
+
+func TestSamplingPacksCells(t *testing.T) {
+	r, _ := NewWithSampling(8, 64)
+	ref := New(8)
+
+	out := make([]byte, 32) // four generations
+	r.Read(out)
+	for g := 0; g < 4; g++ {
+		ref.step()
+		for i := 0; i < 64; i++ {
+			bit := g*64 + i
+			if got := out[bit/8]>>(bit%8)&1 == 1; got != cell(ref, i*4+2) {
+				t.Fatalf("generation %d sample %d does not match cell %d", g, i, i*4+2)
+			}
+		}
+	}
+}
+
+func TestNewWithSamplingValidation(t *testing.T) {
+	for _, keep := range []int{0, -1, 3, 100, 512} {
+		if _, err := NewWithSampling(1, keep); err == nil {
+			t.Errorf("keepBits %d accepted", keep)
+		}
+	}
+}
+
+func TestMaskMatchesSampling(t *testing.T) {
+	read := func(r *RNG, err error) []byte {
+		if err != nil {
+			t.Fatal(err)
+		}
+		data := make([]byte, 4096)
+		r.Read(data)
+		return data
+	}
+
+	center := read(NewWithMask(6, [4]uint64{0, 0, 1 << 63, 0}))
+	if !bytes.Equal(center, read(NewCenterColumn(6), nil)) {
+		t.Error("mask of cell 128 differs from the center column")
+	}
+
+	full := read(NewWithMask(6, [4]uint64{^uint64(0), ^uint64(0), ^uint64(0), ^uint64(0)}))
+	if !bytes.Equal(full, read(NewWithSampling(6, 256))) {
+		t.Error("full mask differs from the raw strip")
+	}
+
+	if _, err := NewWithMask(6, [4]uint64{}); err == nil {
+		t.Error("empty mask accepted")
+	}
+}
+
+func TestMaskPacksCells(t *testing.T) {
+	// Cells 0, 5 and 200
+	r, _ := NewWithMask(9, [4]uint64{1<<63 | 1<<58, 0, 0, 1 << (63 - 200%64)})
+	ref := New(9)
+	cells := []int{0, 5, 200}
+
+	out := make([]byte, 12) // 32 generations
+	r.Read(out)
+	for bit := range len(out) * 8 {
+		if bit%3 == 0 {
+			ref.step()
+		}
+		c := cells[bit%3]
+		if got := out[bit/8]>>(bit%8)&1 == 1; got != cell(ref, c) {
+			t.Fatalf("bit %d does not match cell %d", bit, c)
+		}
+	}
+}
+
+func TestMarshalMask(t *testing.T) {
+	orig, _ := NewWithMask(5, [4]uint64{0xF0F0, 0, 1 << 40, 0})
+	orig.Read(make([]byte, 3))
+
+	data, _ := orig.MarshalBinary()
+	restored := New(0)
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+
+	want, got := make([]byte, 64), make([]byte, 64)
+	orig.Read(want)
+	restored.Read(got)
+	if !bytes.Equal(got, want) {
+		t.Error("mask mode lost in snapshot round trip")
+	}
+
+	// A mask on a mixed-mode snapshot is corrupt
+	data, _ = New(5).MarshalBinary()
+	data[29+32] = 1
+	if err := (&RNG{}).UnmarshalBinary(data); err == nil {
+		t.Error("mask accepted in mixed mode")
+	}
+}
+
+func TestBitOrderReversesBytes(t *testing.T) {
+	lsb, msb := New(77), NewWithBitOrder(77, true)
+
+	// Odd sizes exercise the buffered and word-at-a-time paths too
+	for _, n := range []int{1, 7, 32, 100, 4096} {
+		a, b := make([]byte, n), make([]byte, n)
+		lsb.Read(a)
+		msb.Read(b)
+		for i := range a {
+			if b[i] != bits.Reverse8(a[i]) {
+				t.Fatalf("read of %d: byte %d is %08b, want %08b", n, i, b[i], bits.Reverse8(a[i]))
+			}
+		}
+	}
+
+	a, b := make([]uint64, 9), make([]uint64, 9)
+	lsb.FillUint64s(a)
+	msb.FillUint64s(b)
+	for i := range a {
+		if b[i] != bits.ReverseBytes64(bits.Reverse64(a[i])) {
+			t.Fatalf("word %d is not bit-reversed per byte", i)
+		}
+	}
+
+	if !bytes.Equal(readN(NewWithBitOrder(77, false), 100), readN(New(77), 100)) {
+		t.Error("LSB-first order differs from New")
+	}
+
+	data, _ := msb.MarshalBinary()
+	restored := &RNG{}
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(readN(restored, 100), readN(msb, 100)) {
+		t.Error("bit order lost in snapshot round trip")
+	}
+}
+
+func readN(r *RNG, n int) []byte {
+	p := make([]byte, n)
+	r.Read(p)
+	return p
+}
+
+func TestReadCellOrderMatchesStrip(t *testing.T) {
+	const gens = 6
+	rng, ref := New(31), New(31)
+
+	// Odd read sizes must not change the stream
+	p := make([]byte, 32*gens)
+	for i, n := 0, 0; i < len(p); i += n {
+		n = min(13, len(p)-i)
+		rng.ReadCellOrder(p[i : i+n])
+	}
+
+	for g := 0; g < gens; g++ {
+		strip := ref.StepGeneration()
+		for c := 0; c < 256; c++ {
+			want := strip[c/64] >> (63 - c%64) & 1
+			got := uint64(p[g*32+c/8]>>(7-c%8)) & 1
+			if got != want {
+				t.Fatalf("generation %d cell %d = %d, want %d", g+1, c, got, want)
+			}
+		}
+	}
+
+	// The same bytes as the raw strip, first cell in bit 7
+	raw, _ := NewWithSampling(31, 256)
+	raw.msbFirst = true
+	if !bytes.Equal(p, readN(raw, len(p))) {
+		t.Error("ReadCellOrder differs from the raw strip read MSB first")
+	}
+}
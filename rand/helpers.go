@@ -0,0 +1,155 @@
+package rand
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"math"
+)
+
+// This file contains convenience helpers built on the core methods.
+
+// WeightedIndex returns a random index into weights, chosen with probability
+// proportional to its weight
+// Weights need not sum to 1. One Float64 is drawn per call. Panics if a
+// weight is negative, NaN or infinite, if all weights are zero, or if the
+// weights sum to more than math.MaxFloat64.
+func (r *RNG) WeightedIndex(weights []float64) int {
+	total := 0.0
+	last := -1 // last index with a non-zero weight
+	for i, w := range weights {
+		if !(w >= 0) || w > math.MaxFloat64 {
+			panic("invalid weight in WeightedIndex")
+		}
+		if w > 0 {
+			total += w
+			last = i
+		}
+	}
+	if last < 0 {
+		panic("WeightedIndex needs a positive weight")
+	}
+	if total > math.MaxFloat64 {
+		panic("WeightedIndex weights overflow")
+	}
+
+	x := r.Float64() * total
+	for i, w := range weights {
+		if x < w {
+			return i
+		}
+		x -= w
+	}
+	return last // rounding left x just above the final weight
+}
+
+// Bytes returns n random bytes in a newly allocated slice
+// It is equivalent to make([]byte, n) followed by Read, allocation included;
+// in hot paths prefer Read into a reused buffer.
+func (r *RNG) Bytes(n int) []byte {
+	b := make([]byte, n)
+	r.Read(b)
+	return b
+}
+
+// FillBits fills dst with the next bits of the byte stream, one per element
+// Bits are unpacked from the bytes Read would return, least significant bit
+// first, so dst[i] is bit i%8 of byte i/8. Whole bytes are consumed: if
+// len(dst) is not a multiple of 8, the unused high bits of the last byte are
+// dropped.
+func (r *RNG) FillBits(dst []bool) {
+	i := 0
+	for ; len(dst)-i >= 64; i += 64 {
+		w := r.Uint64()
+		for j := range 64 {
+			dst[i+j] = w>>j&1 == 1
+		}
+	}
+	for ; i < len(dst); i += 8 {
+		b, _ := r.ReadByte()
+		for j := 0; j < 8 && i+j < len(dst); j++ {
+			dst[i+j] = b>>j&1 == 1
+		}
+	}
+}
+
+// Token returns nBytes random bytes encoded as unpadded URL-safe base64,
+// handy for identifiers and nonces
+// The token is as deterministic as the generator: anyone who knows the seed
+// can reproduce it. Only use it for secrets when r was seeded from a strong
+// source such as NewFromReader(crypto/rand.Reader), and even then prefer
+// crypto/rand for anything security-critical.
+func (r *RNG) Token(nBytes int) string {
+	return base64.RawURLEncoding.EncodeToString(r.Bytes(nBytes))
+}
+
+// UUID returns a random version 4 UUID in canonical 8-4-4-4-12 hex form
+// It draws 16 bytes and sets the version and variant bits per RFC 4122, so
+// a given seed always yields the same sequence of UUIDs. That makes them
+// suitable for golden tests, not for identifiers that must be unguessable.
+func (r *RNG) UUID() string {
+	var u [16]byte
+	r.Read(u[:])
+	u[6] = u[6]&0x0f | 0x40 // version 4
+	u[8] = u[8]&0x3f | 0x80 // variant 10xx
+
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
+
+// Roll returns the result of rolling a die with the given number of sides,
+// uniformly in [1, sides]
+// Panics if sides < 1
+func (r *RNG) Roll(sides int) int {
+	if sides < 1 {
+		panic("invalid argument to Roll")
+	}
+	return r.Intn(sides) + 1
+}
+
+// RollN returns count rolls of a die with the given number of sides, in the
+// order Roll would produce them
+// Panics if sides < 1 or count < 0
+func (r *RNG) RollN(count, sides int) []int {
+	if sides < 1 || count < 0 {
+		panic("invalid argument to RollN")
+	}
+	rolls := make([]int, count)
+	for i := range rolls {
+		rolls[i] = r.Roll(sides)
+	}
+	return rolls
+}
+
+// Choice returns a uniformly chosen element of items
+// Panics if items is empty
+func Choice[T any](r *RNG, items []T) T {
+	if len(items) == 0 {
+		panic("Choice from an empty slice")
+	}
+	return items[r.Intn(len(items))]
+}
+
+// Sample returns k elements of items chosen uniformly without replacement,
+// in random order
+// items is not modified. Panics if k < 0 or k > len(items)
+func Sample[T any](r *RNG, items []T, k int) []T {
+	if k < 0 || k > len(items) {
+		panic("invalid sample size in Sample")
+	}
+	// Partial Fisher-Yates on a copy: the first k slots end up sampled
+	pool := append([]T(nil), items...)
+	for i := range k {
+		j := i + r.Intn(len(pool)-i)
+		pool[i], pool[j] = pool[j], pool[i]
+	}
+	return pool[:k:k]
+}
@@ -0,0 +1,256 @@
+package rand
+
+import (
+	"bytes"
+	"encoding/base64"
+	"math"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestWeightedIndexFrequencies(t *testing.T) {
+	weights := []float64{1, 0, 3, 6} // unnormalized
+	const draws = 1000000
+	r := New(5150)
+	counts := make([]int, len(weights))
+	for range draws {
+		counts[r.WeightedIndex(weights)]++
+	}
+
+	if counts[1] != 0 {
+		t.Errorf("zero-weight index drawn %d times", counts[1])
+	}
+	for i, w := range weights {
+		got, want := float64(counts[i])/draws, w/10
+		if math.Abs(got-want) > 0.003 {
+			t.Errorf("index %d: frequency %.4f, want %.4f", i, got, want)
+		}
+	}
+}
+
+func TestWeightedIndexSingle(t *testing.T) {
+	r := New(1)
+	for range 1000 {
+		if i := r.WeightedIndex([]float64{0, 0, 2.5, 0}); i != 2 {
+			t.Fatalf("WeightedIndex = %d, want 2", i)
+		}
+	}
+}
+
+func TestWeightedIndexPanics(t *testing.T) {
+	for _, weights := range [][]float64{{1, -1}, {0, 0}, nil, {math.NaN()}, {math.Inf(1)}, {math.MaxFloat64, math.MaxFloat64}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("WeightedIndex(%v) did not panic", weights)
+				}
+			}()
+			New(1).WeightedIndex(weights)
+		}()
+	}
+}
+
+func TestBytesMatchesRead(t *testing.T) {
+	want := make([]byte, 100)
+	New(77).Read(want)
+	if got := New(77).Bytes(100); !bytes.Equal(got, want) {
+		t.Error("Bytes(100) differs from Read")
+	}
+	if got := New(77).Bytes(0); len(got) != 0 {
+		t.Errorf("Bytes(0) returned %d bytes", len(got))
+	}
+}
+
+func TestToken(t *testing.T) {
+	for _, n := range []int{1, 16, 32, 33} {
+		token := New(2025).Token(n)
+		raw, err := base64.RawURLEncoding.DecodeString(token)
+		if err != nil {
+			t.Fatalf("Token(%d) = %q: %v", n, token, err)
+		}
+		if len(raw) != n {
+			t.Errorf("Token(%d) decodes to %d bytes", n, len(raw))
+		}
+		want := make([]byte, n)
+		New(2025).Read(want)
+		if !bytes.Equal(raw, want) {
+			t.Errorf("Token(%d) bytes differ from Read", n)
+		}
+	}
+}
+
+func TestUUID(t *testing.T) {
+	// Version 4 in the third group, variant 10xx (8, 9, a or b) in the fourth
+	shape := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	a, b := New(1234), New(1234)
+	seen := make(map[string]bool)
+	for range 1000 {
+		u := a.UUID()
+		if !shape.MatchString(u) {
+			t.Fatalf("UUID %q is not a canonical version 4 UUID", u)
+		}
+		if v := b.UUID(); v != u {
+			t.Fatalf("same seed gave %q and %q", u, v)
+		}
+		if seen[u] {
+			t.Fatalf("UUID %q repeated", u)
+		}
+		seen[u] = true
+	}
+}
+
+func TestRollD6(t *testing.T) {
+	const draws = 600000
+	r := New(66)
+	var counts [7]int
+	for range draws {
+		counts[r.Roll(6)]++
+	}
+	if counts[0] != 0 {
+		t.Fatalf("rolled 0 %d times", counts[0])
+	}
+	for face := 1; face <= 6; face++ {
+		if f := float64(counts[face]) / draws; math.Abs(f-1.0/6) > 0.003 {
+			t.Errorf("face %d: frequency %.4f, want 0.1667", face, f)
+		}
+	}
+}
+
+func TestRollNDeterministic(t *testing.T) {
+	a := New(1234).RollN(100, 20)
+	b := New(1234).RollN(100, 20)
+	if !slices.Equal(a, b) {
+		t.Fatal("equally seeded generators rolled differently")
+	}
+
+	r := New(1234)
+	for i, v := range a {
+		if got := r.Roll(20); got != v {
+			t.Fatalf("roll %d: RollN gave %d, Roll gives %d", i, v, got)
+		}
+	}
+
+	if got := New(1).RollN(5, 1); !slices.Equal(got, []int{1, 1, 1, 1, 1}) {
+		t.Errorf("d1 rolls = %v", got)
+	}
+}
+
+func TestRollPanics(t *testing.T) {
+	for _, f := range []func(){
+		func() { New(1).Roll(0) },
+		func() { New(1).RollN(3, -2) },
+		func() { New(1).RollN(-1, 6) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("invalid roll did not panic")
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestChoiceUniform(t *testing.T) {
+	items := []string{"a", "b", "c", "d", "e"}
+	const draws = 500000
+	r := New(77)
+	counts := map[string]int{}
+	for range draws {
+		counts[Choice(r, items)]++
+	}
+	for _, it := range items {
+		if f := float64(counts[it]) / draws; math.Abs(f-0.2) > 0.003 {
+			t.Errorf("%q: frequency %.4f, want 0.2", it, f)
+		}
+	}
+}
+
+func TestSampleDistinct(t *testing.T) {
+	items := make([]int, 50)
+	for i := range items {
+		items[i] = i * 10
+	}
+	orig := slices.Clone(items)
+
+	r := New(3)
+	for k := 0; k <= len(items); k += 7 {
+		got := Sample(r, items, k)
+		if len(got) != k {
+			t.Fatalf("Sample(k=%d) returned %d elements", k, len(got))
+		}
+		seen := map[int]bool{}
+		for _, v := range got {
+			if seen[v] || !slices.Contains(items, v) {
+				t.Fatalf("Sample(k=%d) = %v has a repeated or foreign element", k, got)
+			}
+			seen[v] = true
+		}
+	}
+	if !slices.Equal(items, orig) {
+		t.Error("Sample modified its input")
+	}
+
+	// Every element is equally likely to be included
+	const rounds = 100000
+	counts := make([]int, 10)
+	for range rounds {
+		for _, v := range Sample(r, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, 3) {
+			counts[v]++
+		}
+	}
+	for v, c := range counts {
+		if f := float64(c) / rounds; math.Abs(f-0.3) > 0.005 {
+			t.Errorf("element %d included with frequency %.4f, want 0.3", v, f)
+		}
+	}
+}
+
+func TestFillBitsMatchesRead(t *testing.T) {
+	for _, r := range []*RNG{New(12), NewWithBitOrder(12, true), NewCenterColumn(12)} {
+		ref := r.Clone()
+		got := make([]bool, 256)
+		r.FillBits(got)
+		want := make([]byte, 32)
+		ref.Read(want)
+		for i, bit := range got {
+			if bit != (want[i/8]>>(i%8)&1 == 1) {
+				t.Fatalf("%s: bit %d = %v, byte %d is %08b", r.Describe(), i, bit, i/8, want[i/8])
+			}
+		}
+	}
+
+	// A partial final byte is consumed whole
+	r, ref := New(13), New(13)
+	got := make([]bool, 13)
+	r.FillBits(got)
+	want := make([]byte, 3)
+	ref.Read(want)
+	for i, bit := range got {
+		if bit != (want[i/8]>>(i%8)&1 == 1) {
+			t.Fatalf("bit %d = %v, byte %d is %08b", i, bit, i/8, want[i/8])
+		}
+	}
+	if b, _ := r.ReadByte(); b != want[2] {
+		t.Errorf("next byte after 13 bits = %#x, want %#x", b, want[2])
+	}
+}
+
+func TestChoiceSamplePanics(t *testing.T) {
+	for _, f := range []func(){
+		func() { Choice(New(1), []int{}) },
+		func() { Sample(New(1), []int{1, 2}, 3) },
+		func() { Sample(New(1), []int{1, 2}, -1) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("invalid call did not panic")
+				}
+			}()
+			f()
+		}()
+	}
+}
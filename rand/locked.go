@@ -0,0 +1,40 @@
+package rand
+
+import "sync"
+
+// LockedRNG wraps an RNG with a mutex so it can be shared between goroutines
+// A bare *RNG is not safe for concurrent use.
+type LockedRNG struct {
+	mu  sync.Mutex
+	rng *RNG
+}
+
+// NewLocked returns a goroutine-safe wrapper around rng
+// rng must not be used directly afterwards.
+func NewLocked(rng *RNG) *LockedRNG {
+	return &LockedRNG{rng: rng}
+}
+
+// Read implements io.Reader interface
+func (l *LockedRNG) Read(buf []byte) (n int, err error) {
+	l.mu.Lock()
+	n, err = l.rng.Read(buf)
+	l.mu.Unlock()
+	return n, err
+}
+
+// Uint64 returns a random uint64
+func (l *LockedRNG) Uint64() uint64 {
+	l.mu.Lock()
+	v := l.rng.Uint64()
+	l.mu.Unlock()
+	return v
+}
+
+// Intn returns a random int in [0, n), without modulo bias
+// Panics if n <= 0
+func (l *LockedRNG) Intn(n int) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rng.Intn(n)
+}
@@ -0,0 +1,48 @@
+package rand
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// Run with -race to check the locking
+func TestLockedRNGConcurrent(t *testing.T) {
+	const (
+		workers = 16
+		rounds  = 2000
+	)
+	l := NewLocked(New(12345))
+
+	var total atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			buf := make([]byte, 1+w*7)
+			for i := 0; i < rounds; i++ {
+				n, err := l.Read(buf)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				total.Add(int64(n))
+				l.Uint64()
+				if v := l.Intn(10); v < 0 || v >= 10 {
+					t.Errorf("Intn(10) = %d", v)
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	want := int64(0)
+	for w := 0; w < workers; w++ {
+		want += int64(1+w*7) * rounds
+	}
+	if total.Load() != want {
+		t.Errorf("read %d bytes, want %d", total.Load(), want)
+	}
+}
@@ -0,0 +1,57 @@
+package rand
+
+import "slices"
+
+// This file contains tools for studying the cycle structure of the strip.
+
+// DetectPeriod evolves the strip seeded with seed and reports the length of
+// the cycle it falls into, using Floyd's cycle detection
+// A finite circular strip must eventually repeat, but for the default
+// 256-bit strip the cycles are far too long to find; found is false if no
+// cycle shows up within maxGens generations.
+func DetectPeriod(seed uint64, maxGens uint64) (period uint64, found bool) {
+	return New(seed).detectPeriod(maxGens)
+}
+
+// Period reports the length of the cycle r's strip falls into, as
+// DetectPeriod does for a seed, searching at most maxGens generations
+// It works for any width or rule and leaves r unchanged. found is false if no
+// cycle shows up within the budget.
+func (r *RNG) Period(maxGens uint64) (period uint64, found bool) {
+	return r.detectPeriod(maxGens)
+}
+
+// detectPeriod runs Floyd's algorithm on copies of r's strip
+func (r *RNG) detectPeriod(maxGens uint64) (period uint64, found bool) {
+	slow, fast := r.Clone(), r.Clone()
+	for range maxGens {
+		slow.step()
+		fast.step()
+		fast.step()
+		if !slices.Equal(slow.state, fast.state) {
+			continue
+		}
+
+		// slow is on the cycle now; walk around it once
+		start := slow.CopyState()
+		for period = 1; period <= maxGens; period++ {
+			slow.step()
+			if slices.Equal(slow.state, start) {
+				return period, true
+			}
+		}
+		break
+	}
+	return 0, false
+}
+
+// Generations returns how many generations the strip has evolved since it
+// was last seeded, not counting warmup
+// The default mode consumes one generation per 32 bytes of output. Cycle
+// lengths of the 256-bit strip are not known and DetectPeriod cannot reach
+// them, so long runs should not be assumed safe: treat more than 2^40
+// generations (32 TiB of output) from a single seed as beyond what has been
+// tested, and reseed or Split before then.
+func (r *RNG) Generations() uint64 {
+	return r.gens
+}
@@ -0,0 +1,91 @@
+package rand
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDetectPeriodShift(t *testing.T) {
+	// Under rule 240 every cell copies its left neighbour, so the strip
+	// rotates one cell per generation. A single live cell on a 128-cell strip
+	// comes back after exactly 128 generations.
+	r, err := NewWithWidth(1, 128)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.radius, r.rule = 1, 240
+	r.state[0], r.state[1] = 1, 0
+
+	if period, found := r.detectPeriod(1000); !found || period != 128 {
+		t.Errorf("period = %d, %v; want 128, true", period, found)
+	}
+	if _, found := r.detectPeriod(100); found {
+		t.Error("found a period longer than the bound")
+	}
+}
+
+func TestDetectPeriodFixedPoint(t *testing.T) {
+	// Rule 204 leaves every cell unchanged
+	r, err := NewWithRule(42, 1, 204)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := r.CopyState()
+	if period, found := r.detectPeriod(10); !found || period != 1 {
+		t.Errorf("period = %d, %v; want 1, true", period, found)
+	}
+	if !slices.Equal(r.CopyState(), before) {
+		t.Error("detectPeriod changed the generator")
+	}
+}
+
+func TestPeriodMethod(t *testing.T) {
+	r, err := NewWithRule(42, 1, 204)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if period, found := r.Period(10); !found || period != 1 {
+		t.Errorf("Period = %d, %v; want 1, true", period, found)
+	}
+	if _, found := New(12345).Period(1000); found {
+		t.Error("default strip reported a period")
+	}
+}
+
+func TestDetectPeriodDefault(t *testing.T) {
+	if period, found := DetectPeriod(12345, 10000); found {
+		t.Errorf("default strip reported period %d", period)
+	}
+}
+
+func TestGenerations(t *testing.T) {
+	r := NewWithWarmup(12345, DefaultWarmup)
+	if g := r.Generations(); g != 0 {
+		t.Fatalf("fresh generator: Generations() = %d, want 0", g)
+	}
+
+	buf := make([]byte, 32)
+	for i := uint64(1); i <= 10; i++ {
+		r.Read(buf)
+		if g := r.Generations(); g != i {
+			t.Fatalf("after %d chunks: Generations() = %d", i, g)
+		}
+	}
+
+	data, err := r.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := new(RNG)
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if g := restored.Generations(); g != 10 {
+		t.Errorf("after round trip: Generations() = %d, want 10", g)
+	}
+
+	r.Reset(1)
+	if g := r.Generations(); g != 0 {
+		t.Errorf("after Reset: Generations() = %d, want 0", g)
+	}
+}
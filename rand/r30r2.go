@@ -2,32 +2,206 @@ package rand
 
 import (
 	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
 	"math/bits"
 )
 
 // RNG implements a 1D cellular automaton (Rule 30) on a circular 256-bit strip
 // Optimized for 64-bit architectures using uint64 words
+// An RNG is not safe for concurrent use; see LockedRNG.
 type RNG struct {
-	state [4]uint64 // 256 bits as 4 × 64-bit words
-	pos   int       // position in state for Uint64() extraction (0-3)
+	state []uint64 // strip as 64-bit words (256 bits = 4 words by default)
+	pos   int      // position in state for Uint64() extraction
+	buf   uint64   // unread bytes of the last word split by a partial Read
+	nbuf  int      // number of unread bytes in buf (0-7)
+
+	radius int    // neighborhood radius of a custom rule (0 = built-in rule)
+	rule   uint64 // Wolfram rule number of a custom rule
+	ruleHi uint64 // bits 64-127 of a radius-3 rule number
+
+	mode  outputMode // how output words are extracted from the strip
+	keep  int        // cells sampled per generation (modeSample, modeMask)
+	left  int        // samples left in the current generation (modeSample, modeMask)
+	cells []int      // masked cells in strip order (modeMask); never modified
+
+	msbFirst bool // reverse the bits of every output byte
+
+	warmup int      // generations discarded after every (re)seed
+	gens   uint64   // generations produced since the last (re)seed
+	origin []uint64 // strip at the last (re)seed, where ReadAt offsets start
 }
 
+// DefaultWidth is the strip width, in bits, used by New
+const DefaultWidth = 256
+
 // New creates a new Rule 30 RNG from a seed
+// Every seed is valid, including 0: the strip words are the seed XORed with
+// distinct fixed patterns, so the strip is never empty. (The CLI's use of 0
+// for a time-based seed is a flag default, not a library rule.)
 func New(seed uint64) *RNG {
-	rng := &RNG{
-		pos: 4, // Force step() on first Uint64() call
+	rng := &RNG{state: make([]uint64, DefaultWidth/64)}
+	rng.init(seed)
+	return rng
+}
+
+// NewWithWidth creates a new Rule 30 RNG on a strip of widthBits cells
+// Supported widths are 128, 256, 512 and 1024 bits. Each generation emits
+// widthBits/8 bytes, so wider strips do more work per step. Width 256 is
+// identical to New.
+func NewWithWidth(seed uint64, widthBits int) (*RNG, error) {
+	switch widthBits {
+	case 128, 256, 512, 1024:
+	default:
+		return nil, fmt.Errorf("rand: unsupported strip width %d (want 128, 256, 512 or 1024)", widthBits)
 	}
+	rng := &RNG{state: make([]uint64, widthBits/64)}
+	rng.init(seed)
+	return rng, nil
+}
 
-	// Initialize state from seed
-	// Use seed to create varied initial patterns
-	rng.state[0] = seed
-	rng.state[1] = seed ^ 0x5555555555555555
-	rng.state[2] = seed ^ 0xAAAAAAAAAAAAAAAA
-	rng.state[3] = seed ^ 0x3333333333333333
+// DefaultWarmup is the recommended warmup for NewWithWarmup
+// Changes spread two cells per generation in each direction, so after 64
+// generations every cell of a 256-bit strip depends on every seed bit.
+const DefaultWarmup = 64
 
+// NewWithWarmup creates a new Rule 30 RNG that evolves the strip gens
+// generations before producing any output
+// The first generations after seeding are strongly shaped by the seed
+// pattern; the mixed output hides this, but raw-cell modes and analyses of
+// the strip benefit from discarding them. DefaultWarmup is a sensible value.
+// The warmup is applied again by Reset and Seed. It panics if gens is
+// negative or above math.MaxInt32.
+func NewWithWarmup(seed uint64, gens int) *RNG {
+	if gens < 0 || gens > math.MaxInt32 {
+		panic("invalid argument to NewWithWarmup")
+	}
+	rng := &RNG{state: make([]uint64, DefaultWidth/64), warmup: gens}
+	rng.init(seed)
 	return rng
 }
 
+// NewFromBytes creates a new Rule 30 RNG seeding all 256 bits of the strip
+// The seed is read as four little-endian uint64 words; shorter seeds are
+// zero-padded. Seeds longer than 32 bytes are rejected, as are all-zero
+// seeds, since an empty strip is a fixed point of the rule.
+func NewFromBytes(seed []byte) (*RNG, error) {
+	if len(seed) > 32 {
+		return nil, fmt.Errorf("rand: seed is %d bytes, at most 32 allowed", len(seed))
+	}
+
+	var padded [32]byte
+	copy(padded[:], seed)
+
+	rng := &RNG{state: make([]uint64, 4), pos: 4}
+	for i := range rng.state {
+		rng.state[i] = binary.LittleEndian.Uint64(padded[i*8:])
+	}
+	if rng.empty() {
+		return nil, errors.New("rand: seed must not be all zeros")
+	}
+	rng.markOrigin()
+	return rng, nil
+}
+
+// NewFromReader creates a new Rule 30 RNG seeded with 32 bytes read from src
+// Useful for unpredictable seeding, e.g. NewFromReader(crypto/rand.Reader)
+func NewFromReader(src io.Reader) (*RNG, error) {
+	var seed [32]byte
+	if _, err := io.ReadFull(src, seed[:]); err != nil {
+		return nil, fmt.Errorf("rand: reading seed: %w", err)
+	}
+	return NewFromBytes(seed[:])
+}
+
+// Reset re-seeds the RNG in place, discarding any buffered bytes
+// Afterwards r behaves exactly like New(seed) (or the constructor r came
+// from, with the same options), without allocating
+func (r *RNG) Reset(seed uint64) {
+	r.init(seed)
+}
+
+// Describe returns a one-line summary of r's configuration, for experiment
+// logs, e.g. "R30R2 width=256 radius=2 rule=4261478910 sampling=full"
+// The rule is the Wolfram rule number actually applied (RuleR30R2 for the
+// built-in rule). sampling is "full" for the mixed output, the number of
+// cells kept per generation for NewWithSampling, or "mask:" and the cell
+// count for NewWithMask. A non-default bit order and warmup are appended.
+func (r *RNG) Describe() string {
+	radius, rule := 2, fmt.Sprint(RuleR30R2)
+	if r.radius != 0 {
+		radius, rule = r.radius, ruleString(r.rule, r.ruleHi)
+	}
+
+	var sampling string
+	switch r.mode {
+	case modeMixed:
+		sampling = "full"
+	case modeSample:
+		sampling = fmt.Sprint(r.keep)
+	case modeMask:
+		sampling = fmt.Sprintf("mask:%d", r.keep)
+	}
+
+	desc := fmt.Sprintf("R30R2 width=%d radius=%d rule=%s sampling=%s", 64*len(r.state), radius, rule, sampling)
+	if r.msbFirst {
+		desc += " bitorder=msb"
+	}
+	if r.warmup > 0 {
+		desc += fmt.Sprintf(" warmup=%d", r.warmup)
+	}
+	return desc
+}
+
+// init initializes the strip from a 64-bit seed
+func (r *RNG) init(seed uint64) {
+	// Use seed to create varied initial patterns
+	r.state[0] = seed
+	r.state[1] = seed ^ 0x5555555555555555
+	if len(r.state) > 2 {
+		r.state[2] = seed ^ 0xAAAAAAAAAAAAAAAA
+		r.state[3] = seed ^ 0x3333333333333333
+	}
+	// Wider strips get distinct patterns per word; repeating the first four
+	// would give a periodic strip, which the rule keeps periodic forever
+	for i := 4; i < len(r.state); i++ {
+		r.state[i] = seed ^ splitMix(uint64(i))
+	}
+
+	for i := 0; i < r.warmup; i++ {
+		r.step()
+	}
+
+	r.gens = 0
+	r.pos = len(r.state) // Force step() on first Uint64() call
+	r.buf = 0
+	r.nbuf = 0
+	r.left = 0
+	r.markOrigin()
+}
+
+// markOrigin records the current strip as the start of the stream
+// It reuses r.origin when it fits, so reseeding does not allocate.
+func (r *RNG) markOrigin() {
+	if len(r.origin) != len(r.state) {
+		r.origin = make([]uint64, len(r.state))
+	}
+	copy(r.origin, r.state)
+}
+
+// empty reports whether every cell of the strip is zero
+// An empty strip is a fixed point of the rule and only ever produces zeros.
+func (r *RNG) empty() bool {
+	for _, w := range r.state {
+		if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
 // step applies radius-2 CA with non-linear Rule 30 variant to all 256 bits in parallel
 // Radius-2 rule: new_bit = (left2 XOR left1) XOR ((center OR right1) OR right2)
 // Non-linear extension of Rule 30 for better randomness
@@ -35,12 +209,24 @@ func New(seed uint64) *RNG {
 //
 //go:noinline
 func (r *RNG) step() {
+	r.gens++
+	if len(r.state) != 4 || r.radius != 0 {
+		r.stepWords()
+		return
+	}
+
+	st := (*[4]uint64)(r.state)
+	if useAVX2 {
+		stepAVX2(st)
+		return
+	}
+
 	// Fully unrolled loop for maximum performance
 	// Cache state words locally (cheaper than repeated array indexing)
-	s0 := r.state[0]
-	s1 := r.state[1]
-	s2 := r.state[2]
-	s3 := r.state[3]
+	s0 := st[0]
+	s1 := st[1]
+	s2 := st[2]
+	s3 := st[3]
 
 	// Word 0: radius-2 neighborhood wraps from word 3 to word 1
 	left2_0 := (s0 >> 2) | (s3 << 62)
@@ -76,10 +262,39 @@ func (r *RNG) step() {
 
 	// Store pure CA output without mixing
 	// Mixing is applied at output time in mix() function
-	r.state[0] = new0
-	r.state[1] = new1
-	r.state[2] = new2
-	r.state[3] = new3
+	st[0] = new0
+	st[1] = new1
+	st[2] = new2
+	st[3] = new3
+}
+
+// stepWords is the looped form of step for strips of any number of words
+// Each word only needs its two neighbours, so the strip is updated in place
+// while remembering the original values of the previous and first words.
+func (r *RNG) stepWords() {
+	s := r.state
+	n := len(s)
+	first := s[0]
+	prev := s[n-1]
+	for i := 0; i < n; i++ {
+		cur := s[i]
+		next := first
+		if i+1 < n {
+			next = s[i+1]
+		}
+
+		if r.radius != 0 {
+			s[i] = r.applyRule(prev, cur, next)
+		} else {
+			left2 := (cur >> 2) | (prev << 62)
+			left1 := (cur >> 1) | (prev << 63)
+			right1 := (cur << 1) | (next >> 63)
+			right2 := (cur << 2) | (next >> 62)
+			s[i] = (left2 ^ left1) ^ ((cur | right1) | right2)
+		}
+
+		prev = cur
+	}
 }
 
 // mix applies a diffusion function to improve output quality
@@ -94,9 +309,44 @@ func mix(x uint64) uint64 {
 
 // Uint64 returns a random uint64
 // Applies diffusion function to CA output for better statistical quality
+// It takes the next eight bytes of the byte stream Read produces, in
+// little-endian order, so Uint64, Uint32, ReadByte and Read calls can be
+// interleaved freely and still form one contiguous stream. After a partial
+// Read, the buffered bytes become the low bytes of the result.
 func (r *RNG) Uint64() uint64 {
-	// Generate new state if we've exhausted all 4 uint64 values
-	if r.pos >= 4 {
+	if r.nbuf != 0 || r.mode != modeMixed || r.msbFirst {
+		return r.slowUint64()
+	}
+	return r.mixedWord()
+}
+
+// slowUint64 is Uint64 for buffered bytes and the non-default output modes
+func (r *RNG) slowUint64() uint64 {
+	if r.nbuf == 0 {
+		return r.word()
+	}
+
+	// Top up the buffered bytes with the low bytes of the next word and
+	// buffer the rest of it
+	w := r.word()
+	shift := 8 * uint(r.nbuf)
+	v := r.buf | w<<shift
+	r.buf = w >> (64 - shift)
+	return v
+}
+
+// word returns the next whole output word, ignoring buffered bytes
+func (r *RNG) word() uint64 {
+	if r.mode != modeMixed || r.msbFirst {
+		return r.extract()
+	}
+	return r.mixedWord()
+}
+
+// mixedWord returns the next strip word through mix(), stepping as needed
+func (r *RNG) mixedWord() uint64 {
+	// Generate new state if we've exhausted all the strip's words
+	if r.pos >= len(r.state) {
 		r.step()
 		r.pos = 0
 	}
@@ -107,47 +357,206 @@ func (r *RNG) Uint64() uint64 {
 	return mix(val)
 }
 
+// FillUint64s fills dst with the values that len(dst) Uint64 calls would
+// return
+// Whole generations are copied four words at a time, avoiding the per-call
+// overhead of Uint64.
+func (r *RNG) FillUint64s(dst []uint64) {
+	i := 0
+	words := len(r.state)
+	if r.mode == modeMixed && !r.msbFirst && r.nbuf == 0 {
+		// Finish the current generation
+		for ; i < len(dst) && r.pos < words; i++ {
+			dst[i] = mix(r.state[r.pos])
+			r.pos++
+		}
+
+		// Whole generations
+		for ; len(dst)-i >= words; i += words {
+			r.step()
+			if words == 4 {
+				st := (*[4]uint64)(r.state)
+				out := (*[4]uint64)(dst[i:])
+				out[0] = mix(st[0])
+				out[1] = mix(st[1])
+				out[2] = mix(st[2])
+				out[3] = mix(st[3])
+			} else {
+				for j, w := range r.state {
+					dst[i+j] = mix(w)
+				}
+			}
+		}
+	}
+
+	for ; i < len(dst); i++ {
+		dst[i] = r.Uint64()
+	}
+}
+
 // Read implements io.Reader interface
 // Optimized to process in 32-byte chunks (one full step() worth) to minimize
 // function call overhead and branch checks.
+// Bytes of a word that don't fit in buf are kept for the next Read, so
+// consecutive reads form one contiguous byte stream regardless of their sizes.
+// The byte stream is the Uint64 stream in little-endian order: the first 8
+// bytes are binary.LittleEndian.PutUint64 of the first Uint64 of an equally
+// seeded RNG, in every output mode.
+// The stream is infinite, so Read always fills buf and returns len(buf), nil;
+// callers need not check the error.
 func (r *RNG) Read(buf []byte) (n int, err error) {
 	i := 0
 	limit := len(buf)
 
-	// Fast path: Process full 32-byte chunks (4 × uint64)
-	// Only use batch processing when position is aligned (pos == 0 or >= 4)
-	for limit-i >= 32 && (r.pos == 0 || r.pos >= 4) {
-		if r.pos >= 4 {
+	// Serve bytes left over from a previous partial read
+	for r.nbuf > 0 && i < limit {
+		buf[i] = byte(r.buf)
+		r.buf >>= 8
+		r.nbuf--
+		i++
+	}
+
+	// Fast path: Process full generations (32-byte chunks for 4 × uint64)
+	// Only use batch processing when position is aligned (pos == 0 or exhausted)
+	words := len(r.state)
+	chunk := words * 8
+	for r.mode == modeMixed && !r.msbFirst && limit-i >= chunk && (r.pos == 0 || r.pos >= words) {
+		if r.pos >= words {
 			r.step()
 			r.pos = 0
 		}
 
-		// Unroll: write all 4 words at once with mixing
-		// This is safe because we know r.pos == 0
-		binary.LittleEndian.PutUint64(buf[i:], mix(r.state[0]))
-		binary.LittleEndian.PutUint64(buf[i+8:], mix(r.state[1]))
-		binary.LittleEndian.PutUint64(buf[i+16:], mix(r.state[2]))
-		binary.LittleEndian.PutUint64(buf[i+24:], mix(r.state[3]))
+		if words == 4 {
+			// Unroll: write all 4 words at once with mixing
+			// This is safe because we know r.pos == 0
+			st := (*[4]uint64)(r.state)
+			out := (*[32]byte)(buf[i:])
+			binary.LittleEndian.PutUint64(out[0:], mix(st[0]))
+			binary.LittleEndian.PutUint64(out[8:], mix(st[1]))
+			binary.LittleEndian.PutUint64(out[16:], mix(st[2]))
+			binary.LittleEndian.PutUint64(out[24:], mix(st[3]))
+		} else {
+			for j, w := range r.state {
+				binary.LittleEndian.PutUint64(buf[i+j*8:], mix(w))
+			}
+		}
 
-		i += 32
-		r.pos = 4 // Mark state as exhausted
+		i += chunk
+		r.pos = words // Mark state as exhausted
 	}
 
 	// Handle remaining 8-byte chunks (or any unaligned position)
 	for limit-i >= 8 {
-		val := r.Uint64()
+		val := r.word()
 		binary.LittleEndian.PutUint64(buf[i:], val)
 		i += 8
 	}
 
-	// Handle the remaining tail bytes, if any
+	// Handle the remaining tail bytes, if any, keeping the rest of the word
 	if rem := limit - i; rem > 0 {
-		val := r.Uint64()
+		val := r.word()
 		for j := 0; j < rem; j++ {
 			buf[i+j] = byte(val)
 			val >>= 8
 		}
+		r.buf = val
+		r.nbuf = 8 - rem
 	}
 
 	return limit, nil
 }
+
+var _ io.ByteReader = (*RNG)(nil)
+
+// ReadByte returns the next byte of the stream, implementing io.ByteReader
+// It shares Read's buffer, so ReadByte and Read calls can be mixed freely and
+// a new word is only drawn every eight bytes. It never returns an error.
+func (r *RNG) ReadByte() (byte, error) {
+	if r.nbuf == 0 {
+		r.buf = r.word()
+		r.nbuf = 8
+	}
+	b := byte(r.buf)
+	r.buf >>= 8
+	r.nbuf--
+	return b, nil
+}
+
+// Skip advances the stream by nBytes without producing output
+// The RNG is left in exactly the state a Read of nBytes would leave it in,
+// including any bytes buffered from a word that is only partially consumed.
+func (r *RNG) Skip(nBytes uint64) {
+	// Drop bytes left over from a previous partial read first
+	if nBytes <= uint64(r.nbuf) {
+		r.buf >>= 8 * nBytes
+		r.nbuf -= int(nBytes)
+		return
+	}
+	nBytes -= uint64(r.nbuf)
+	r.buf = 0
+	r.nbuf = 0
+
+	words := nBytes / 8
+	rem := nBytes % 8
+	n := len(r.state)
+
+	// Extraction modes have no fixed words-per-generation ratio
+	if r.mode != modeMixed {
+		for ; words > 0; words-- {
+			r.extract()
+		}
+	}
+
+	// Use up the words left in the current generation first
+	if r.pos < n {
+		avail := uint64(n - r.pos)
+		if words <= avail {
+			r.pos += int(words)
+			words = 0
+		} else {
+			words -= avail
+			r.pos = n
+		}
+	}
+
+	// Jump over whole generations (4 words = 32 bytes each by default)
+	for ; words > uint64(n); words -= uint64(n) {
+		r.step()
+	}
+
+	// Step into the final, possibly partial, generation
+	if words > 0 {
+		r.step()
+		r.pos = int(words)
+	}
+
+	// Split the next word, buffering its unread bytes as Read does
+	if rem > 0 {
+		r.buf = r.word() >> (8 * rem)
+		r.nbuf = 8 - int(rem)
+	}
+}
+
+// Discard skips the next n bytes of the stream, like bufio.Reader.Discard
+// It always discards exactly n bytes (0 if n is negative) and never fails.
+func (r *RNG) Discard(n int) (discarded int, err error) {
+	if n <= 0 {
+		return 0, nil
+	}
+	r.Skip(uint64(n))
+	return n, nil
+}
+
+// StepGeneration advances the strip by exactly one generation and returns a
+// copy of the new strip, laid out as in CopyState
+// It is meant for studying the automaton itself: output not yet consumed
+// from the previous generation is discarded, so the next Read starts from
+// the generation after the one returned.
+func (r *RNG) StepGeneration() []uint64 {
+	r.step()
+	r.pos = len(r.state)
+	r.buf = 0
+	r.nbuf = 0
+	r.left = 0
+	return r.CopyState()
+}
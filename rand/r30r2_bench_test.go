@@ -3,6 +3,7 @@ package rand
 import (
 	cryptorand "crypto/rand"
 	"encoding/binary"
+	"fmt"
 	"io"
 	mathrand "math/rand"
 	mathrandv2 "math/rand/v2"
@@ -27,6 +28,7 @@ func BenchmarkR30R2_Read32KB(b *testing.B) {
 func BenchmarkR30R2_Read1KB(b *testing.B) {
 	rng := New(67890)
 	buf := make([]byte, 1<<10)
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		if _, err := rng.Read(buf); err != nil {
@@ -35,14 +37,105 @@ func BenchmarkR30R2_Read1KB(b *testing.B) {
 	}
 }
 
+// discardN discards writes until it has seen n bytes, then fails
+type discardN struct{ n int64 }
+
+func (d *discardN) Write(p []byte) (int, error) {
+	if d.n -= int64(len(p)); d.n < 0 {
+		return len(p) + int(d.n), io.EOF
+	}
+	return len(p), nil
+}
+
+func BenchmarkR30R2_WriteTo(b *testing.B) {
+	rng := New(12345)
+	b.SetBytes(32 << 10)
+	b.ResetTimer()
+	rng.WriteTo(&discardN{n: int64(b.N) << 15})
+}
+
+// BenchmarkR30R2_ReadWriteLoop is the Read-then-Write loop WriteTo replaces,
+// with a buffer that is not a whole number of generations
+func BenchmarkR30R2_ReadWriteLoop(b *testing.B) {
+	rng := New(12345)
+	buf := make([]byte, 1000)
+	b.SetBytes(32 << 10)
+	b.ResetTimer()
+	io.CopyBuffer(&discardN{n: int64(b.N) << 15}, struct{ io.Reader }{rng}, buf)
+}
+
 func BenchmarkR30R2_Uint64(b *testing.B) {
 	rng := New(42)
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		_ = rng.Uint64()
 	}
 }
 
+func BenchmarkR30R2_Step(b *testing.B) {
+	rng := New(42)
+	b.SetBytes(DefaultWidth / 8)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rng.step()
+	}
+}
+
+func BenchmarkR30R2_Step_Reference(b *testing.B) {
+	strip := New(42).CopyState()
+	b.SetBytes(DefaultWidth / 8)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		strip = referenceStep(strip)
+	}
+}
+
+func BenchmarkR30R2_FillUint64s(b *testing.B) {
+	rng := New(42)
+	dst := make([]uint64, 4096)
+	b.SetBytes(int64(8 * len(dst)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rng.FillUint64s(dst)
+	}
+}
+
+func BenchmarkR30R2_Uint64Loop(b *testing.B) {
+	rng := New(42)
+	dst := make([]uint64, 4096)
+	b.SetBytes(int64(8 * len(dst)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range dst {
+			dst[j] = rng.Uint64()
+		}
+	}
+}
+
+func BenchmarkR30R2_Reset(b *testing.B) {
+	rng := New(42)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rng.Reset(uint64(i))
+	}
+}
+
+func BenchmarkR30R2_Width(b *testing.B) {
+	for _, width := range []int{128, 256, 512, 1024} {
+		b.Run(fmt.Sprint(width), func(b *testing.B) {
+			rng, _ := NewWithWidth(12345, width)
+			buf := make([]byte, 32<<10)
+			b.SetBytes(int64(len(buf)))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				rng.Read(buf)
+			}
+		})
+	}
+}
+
 // ====================
 // math/rand Benchmarks
 // ====================
@@ -0,0 +1,538 @@
+package rand
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/vrypan/r30r2/stats"
+)
+
+func TestSkipMatchesRead(t *testing.T) {
+	for _, n := range []uint64{0, 1, 5, 8, 31, 32, 33, 64, 100, 1000, 1001, 4096} {
+		for _, pre := range []int{0, 3, 8, 24, 29, 32} {
+			r1 := New(12345)
+			r2 := New(12345)
+			io.ReadFull(r1, make([]byte, pre))
+			io.ReadFull(r2, make([]byte, pre))
+
+			r1.Skip(n)
+			io.ReadFull(r2, make([]byte, n))
+
+			if !slices.Equal(r1.state, r2.state) || r1.pos != r2.pos || r1.buf != r2.buf || r1.nbuf != r2.nbuf {
+				t.Fatalf("Skip(%d) after %d bytes: state mismatch", n, pre)
+			}
+			a, b := make([]byte, 40), make([]byte, 40)
+			r1.Read(a)
+			r2.Read(b)
+			if !bytes.Equal(a, b) {
+				t.Fatalf("Skip(%d) after %d bytes: next output %x != %x", n, pre, a, b)
+			}
+		}
+	}
+}
+
+func TestReadSplitPoints(t *testing.T) {
+	ref := make([]byte, 200)
+	New(321).Read(ref)
+
+	// Two reads split anywhere, including across generation boundaries
+	for split := 0; split <= len(ref); split++ {
+		r := New(321)
+		got := make([]byte, len(ref))
+		r.Read(got[:split])
+		r.Read(got[split:])
+		if !bytes.Equal(got, ref) {
+			t.Fatalf("reads of %d and %d bytes differ from one read of %d", split, len(ref)-split, len(ref))
+		}
+	}
+
+	// Runs of short reads of every length below a generation
+	for size := 1; size < 32; size++ {
+		r := New(321)
+		var got []byte
+		for len(got) < len(ref) {
+			p := make([]byte, min(size, len(ref)-len(got)))
+			r.Read(p)
+			got = append(got, p...)
+		}
+		if !bytes.Equal(got, ref) {
+			t.Fatalf("reads of %d bytes differ from the contiguous stream", size)
+		}
+	}
+}
+
+func TestReadNeverFails(t *testing.T) {
+	r := New(5)
+	for _, size := range []int{0, 1, 31, 32, 33, 1 << 10, 1 << 25} {
+		if n, err := r.Read(make([]byte, size)); n != size || err != nil {
+			t.Errorf("Read of %d bytes = %d, %v; want %d, nil", size, n, err, size)
+		}
+	}
+	if n, err := r.Read(nil); n != 0 || err != nil {
+		t.Errorf("Read(nil) = %d, %v; want 0, nil", n, err)
+	}
+
+	// Other output modes share the guarantee
+	c := NewCenterColumn(5)
+	if n, err := c.Read(make([]byte, 1001)); n != 1001 || err != nil {
+		t.Errorf("center column Read = %d, %v; want 1001, nil", n, err)
+	}
+}
+
+func TestReadIsLittleEndianUint64(t *testing.T) {
+	sampled, _ := NewWithSampling(3, 64)
+	masked, _ := NewWithMask(3, [4]uint64{1, 2, 3, 4})
+	wide, _ := NewWithWidth(3, 1024)
+	makers := map[string]func() *RNG{
+		"mixed":    func() *RNG { return New(3) },
+		"wide":     func() *RNG { return wide.Clone() },
+		"sampled":  func() *RNG { return sampled.Clone() },
+		"masked":   func() *RNG { return masked.Clone() },
+		"msbFirst": func() *RNG { return NewWithBitOrder(3, true) },
+	}
+	for name, mk := range makers {
+		data := make([]byte, 8*40)
+		mk().Read(data)
+		words := mk()
+		for i := 0; i < len(data); i += 8 {
+			if got, want := binary.LittleEndian.Uint64(data[i:]), words.Uint64(); got != want {
+				t.Fatalf("%s: bytes %d-%d are %#x, want little-endian Uint64 %#x", name, i, i+7, got, want)
+			}
+		}
+	}
+}
+
+func TestReadDiscardInterleaved(t *testing.T) {
+	ref := make([]byte, 4096)
+	New(777).Read(ref)
+
+	r := New(777)
+	off := 0
+	sizes := []int{7, 13, 100, 1, 32, 64, 5, 9, 250}
+	for i := 0; off < 3000; i++ {
+		n := sizes[i%len(sizes)]
+		if i%2 == 0 {
+			got := make([]byte, n)
+			r.Read(got)
+			if !bytes.Equal(got, ref[off:off+n]) {
+				t.Fatalf("read of %d at offset %d does not match reference", n, off)
+			}
+		} else {
+			d, err := r.Discard(n)
+			if d != n || err != nil {
+				t.Fatalf("Discard(%d) = %d, %v", n, d, err)
+			}
+		}
+		off += n
+	}
+}
+
+func TestNewFromBytesHighWordMatters(t *testing.T) {
+	a := make([]byte, 32)
+	b := make([]byte, 32)
+	for i := range a {
+		a[i] = byte(i + 1)
+		b[i] = byte(i + 1)
+	}
+	b[31] ^= 0x80 // differ only in the top word
+
+	ra, err := NewFromBytes(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rb, err := NewFromBytes(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Compare the whole first generation: a single step only carries the
+	// change two cells into the neighbouring words
+	oa, ob := make([]byte, 32), make([]byte, 32)
+	ra.Read(oa)
+	rb.Read(ob)
+	if bytes.Equal(oa, ob) {
+		t.Error("seeds differing in the top word produced the same first output")
+	}
+}
+
+func TestNewFromBytesErrors(t *testing.T) {
+	if _, err := NewFromBytes(make([]byte, 33)); err == nil {
+		t.Error("33-byte seed accepted")
+	}
+	if _, err := NewFromBytes(make([]byte, 32)); err == nil {
+		t.Error("all-zero seed accepted")
+	}
+	if _, err := NewFromBytes([]byte{1}); err != nil {
+		t.Errorf("short seed rejected: %v", err)
+	}
+}
+
+func TestNewFromReaderDeterministic(t *testing.T) {
+	seed := bytes.Repeat([]byte{0xA5, 0x3C}, 16)
+
+	a, err := NewFromReader(bytes.NewReader(seed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewFromBytes(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oa, ob := make([]byte, 1024), make([]byte, 1024)
+	a.Read(oa)
+	b.Read(ob)
+	if !bytes.Equal(oa, ob) {
+		t.Error("NewFromReader stream differs from NewFromBytes with the same 32 bytes")
+	}
+}
+
+func TestNewFromReaderShort(t *testing.T) {
+	_, err := NewFromReader(bytes.NewReader(make([]byte, 16)))
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("short reader: got %v, want io.ErrUnexpectedEOF", err)
+	}
+}
+
+func TestResetMatchesNew(t *testing.T) {
+	r := New(1)
+	r.Read(make([]byte, 45)) // leave bytes buffered
+	r.Reset(99)
+
+	got, want := make([]byte, 100), make([]byte, 100)
+	r.Read(got)
+	New(99).Read(want)
+	if !bytes.Equal(got, want) {
+		t.Error("Reset(99) stream differs from New(99)")
+	}
+
+	if allocs := testing.AllocsPerRun(100, func() { r.Reset(7) }); allocs != 0 {
+		t.Errorf("Reset allocates %v times per call", allocs)
+	}
+}
+
+func TestNewWithWidth(t *testing.T) {
+	for _, width := range []int{128, 256, 512, 1024} {
+		r, err := NewWithWidth(12345, width)
+		if err != nil {
+			t.Fatalf("width %d: %v", width, err)
+		}
+		data := make([]byte, 1<<20)
+		r.Read(data)
+
+		// 330.5 is the p=0.001 critical value for 255 degrees of freedom
+		if chi := stats.ChiSquareUniform(data); chi > 330.5 {
+			t.Errorf("width %d: chi-square = %.1f", width, chi)
+		}
+	}
+
+	for _, width := range []int{0, 64, 100, 2048} {
+		if _, err := NewWithWidth(1, width); err == nil {
+			t.Errorf("width %d accepted", width)
+		}
+	}
+}
+
+func TestWidth256MatchesNew(t *testing.T) {
+	r, _ := NewWithWidth(4242, 256)
+	got, want := make([]byte, 4096), make([]byte, 4096)
+	r.Read(got)
+	New(4242).Read(want)
+	if !bytes.Equal(got, want) {
+		t.Error("NewWithWidth(s, 256) differs from New(s)")
+	}
+}
+
+// The looped step must agree with the unrolled one on the default strip
+func TestStepWordsMatchesStep(t *testing.T) {
+	a, b := New(8080), New(8080)
+	for i := 0; i < 1000; i++ {
+		a.step()
+		b.stepWords()
+		if !slices.Equal(a.state, b.state) {
+			t.Fatalf("generation %d: stepWords diverges from step", i+1)
+		}
+	}
+}
+
+func TestSkipWide(t *testing.T) {
+	r1, _ := NewWithWidth(9, 1024)
+	r2, _ := NewWithWidth(9, 1024)
+	r1.Skip(1000)
+	io.ReadFull(r2, make([]byte, 1000))
+
+	a, b := make([]byte, 300), make([]byte, 300)
+	r1.Read(a)
+	r2.Read(b)
+	if !bytes.Equal(a, b) {
+		t.Error("Skip on a 1024-bit strip does not match Read")
+	}
+}
+
+func TestWarmupDeterministic(t *testing.T) {
+	a, b := make([]byte, 1024), make([]byte, 1024)
+	NewWithWarmup(7, DefaultWarmup).Read(a)
+	NewWithWarmup(7, DefaultWarmup).Read(b)
+	if !bytes.Equal(a, b) {
+		t.Fatal("warmed-up stream is not deterministic")
+	}
+
+	r := NewWithWarmup(7, DefaultWarmup)
+	r.Read(make([]byte, 100))
+	r.Reset(7)
+	r.Read(b)
+	if !bytes.Equal(a, b) {
+		t.Error("Reset does not reapply the warmup")
+	}
+}
+
+func TestWarmupPanics(t *testing.T) {
+	for _, gens := range []int{-1, math.MaxInt32 + 1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewWithWarmup(1, %d) did not panic", gens)
+				}
+			}()
+			NewWithWarmup(1, gens)
+		}()
+	}
+}
+
+func TestWarmupImprovesEarlyEntropy(t *testing.T) {
+	// Look at the raw strip, where the seed pattern is visible
+	first := func(gens int) []byte {
+		r := NewWithWarmup(1, gens)
+		r.mode, r.keep = modeSample, DefaultWidth
+		data := make([]byte, 1024)
+		r.Read(data)
+		return data
+	}
+
+	cold, warm := stats.ShannonEntropy(first(0)), stats.ShannonEntropy(first(DefaultWarmup))
+	t.Logf("first 1KB entropy: no warmup %.3f, warmup %.3f bits/byte", cold, warm)
+	if warm <= cold+0.5 {
+		t.Errorf("warmup did not improve entropy: %.3f -> %.3f", cold, warm)
+	}
+}
+
+func TestZeroSeed(t *testing.T) {
+	r := New(0)
+	if r.empty() {
+		t.Fatal("New(0) has an empty strip")
+	}
+
+	data := make([]byte, 1<<20)
+	r.Read(data)
+	if !slices.ContainsFunc(data, func(b byte) bool { return b != 0 }) {
+		t.Fatal("New(0) produced only zeros")
+	}
+	if e := stats.ShannonEntropy(data); e < 7.999 {
+		t.Errorf("New(0) entropy over 1MB = %.5f bits/byte, want about 8", e)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	wide, _ := NewWithWidth(1, 512)
+	classic, _ := NewWithRule(1, 1, 30)
+	sampled, _ := NewWithSampling(1, 64)
+	masked, _ := NewWithMask(1, [4]uint64{0, 0, 1 << 63, 1})
+	cases := []struct {
+		rng  *RNG
+		want string
+	}{
+		{New(1), "R30R2 width=256 radius=2 rule=4261478910 sampling=full"},
+		{wide, "R30R2 width=512 radius=2 rule=4261478910 sampling=full"},
+		{classic, "R30R2 width=256 radius=1 rule=30 sampling=full"},
+		{sampled, "R30R2 width=256 radius=2 rule=4261478910 sampling=64"},
+		{NewCenterColumn(1), "R30R2 width=256 radius=2 rule=4261478910 sampling=1"},
+		{masked, "R30R2 width=256 radius=2 rule=4261478910 sampling=mask:2"},
+		{NewWithBitOrder(1, true), "R30R2 width=256 radius=2 rule=4261478910 sampling=full bitorder=msb"},
+		{NewWithWarmup(1, 64), "R30R2 width=256 radius=2 rule=4261478910 sampling=full warmup=64"},
+	}
+	for _, c := range cases {
+		if got := c.rng.Describe(); got != c.want {
+			t.Errorf("Describe() = %q, want %q", got, c.want)
+		}
+	}
+}
+
+// referenceStep evolves a strip of any width one generation, one cell at a
+// time, with the radius-2 rule new = (l2 XOR l1) XOR (c OR r1 OR r2)
+func referenceStep(strip []uint64) []uint64 {
+	width := 64 * len(strip)
+	at := func(c int) uint64 {
+		c = (c + width) % width
+		return strip[c/64] >> (63 - c%64) & 1
+	}
+	next := make([]uint64, len(strip))
+	for c := range width {
+		l2, l1, ctr, r1, r2 := at(c-2), at(c-1), at(c), at(c+1), at(c+2)
+		next[c/64] |= ((l2 ^ l1) ^ (ctr | r1 | r2)) << (63 - c%64)
+	}
+	return next
+}
+
+func TestStepGenerationMatchesRule(t *testing.T) {
+	r := New(2024)
+	strip := r.CopyState()
+	for gen := 1; gen <= 100; gen++ {
+		strip = referenceStep(strip)
+		if got := r.StepGeneration(); !slices.Equal(got, strip) {
+			t.Fatalf("generation %d differs from the reference evolution", gen)
+		}
+	}
+	if g := r.Generations(); g != 100 {
+		t.Errorf("Generations() = %d after 100 steps", g)
+	}
+}
+
+func TestReadMatchesReferenceStep(t *testing.T) {
+	const size = 1 << 20
+	got := make([]byte, size)
+	New(31337).Read(got)
+
+	strip := New(31337).CopyState()
+	want := make([]byte, 0, size)
+	for len(want) < size {
+		strip = referenceStep(strip)
+		for _, w := range strip {
+			want = binary.LittleEndian.AppendUint64(want, mix(w))
+		}
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("bit-parallel step output differs from the per-cell reference")
+	}
+}
+
+func TestReadByteMatchesRead(t *testing.T) {
+	want := make([]byte, 1000)
+	New(8080).Read(want)
+
+	r := New(8080)
+	got := make([]byte, 1000)
+	for i := range got {
+		got[i], _ = r.ReadByte()
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("ReadByte stream differs from Read")
+	}
+
+	// Mixed with Read, the stream carries on where the other left off
+	r = New(8080)
+	mixed := make([]byte, 0, 1000)
+	for len(mixed) < 1000 {
+		b, _ := r.ReadByte()
+		mixed = append(mixed, b)
+		chunk := make([]byte, min(len(mixed)%37, 1000-len(mixed)))
+		r.Read(chunk)
+		mixed = append(mixed, chunk...)
+	}
+	if !bytes.Equal(mixed, want) {
+		t.Error("interleaved ReadByte and Read differ from Read")
+	}
+}
+
+func TestInterleavedMethodsShareStream(t *testing.T) {
+	// Each op draws from r and appends the bytes it consumed, little-endian
+	type op func(r *RNG, out []byte) []byte
+	u64 := func(r *RNG, out []byte) []byte { return binary.LittleEndian.AppendUint64(out, r.Uint64()) }
+	u32 := func(r *RNG, out []byte) []byte { return binary.LittleEndian.AppendUint32(out, r.Uint32()) }
+	byt := func(r *RNG, out []byte) []byte { b, _ := r.ReadByte(); return append(out, b) }
+	read := func(n int) op {
+		return func(r *RNG, out []byte) []byte {
+			p := make([]byte, n)
+			r.Read(p)
+			return append(out, p...)
+		}
+	}
+	fill := func(n int) op {
+		return func(r *RNG, out []byte) []byte {
+			words := make([]uint64, n)
+			r.FillUint64s(words)
+			for _, w := range words {
+				out = binary.LittleEndian.AppendUint64(out, w)
+			}
+			return out
+		}
+	}
+
+	sequences := map[string][]op{
+		"Uint64 then Read(24)":  {u64, read(24)},
+		"Read(3) then Uint64":   {read(3), u64, u64, read(5)},
+		"bytes around Uint64":   {byt, u64, byt, byt, u64, u32},
+		"Uint32 straddles":      {read(6), u32, u64, read(1), u32},
+		"Fill after partial":    {read(5), fill(6), read(40), fill(3)},
+		"generation boundaries": {read(31), u64, read(33), u32, u32, fill(4), byt},
+	}
+	for _, mk := range []func() *RNG{
+		func() *RNG { return New(404) },
+		func() *RNG { return NewWithBitOrder(404, true) },
+		func() *RNG { r, _ := NewWithSampling(404, 64); return r },
+	} {
+		for name, ops := range sequences {
+			r := mk()
+			var got []byte
+			for _, f := range ops {
+				got = f(r, got)
+			}
+			if want := readN(mk(), len(got)); !bytes.Equal(got, want) {
+				t.Errorf("%s (%s): stream differs from Read\n got  %x\n want %x", name, r.Describe(), got, want)
+			}
+		}
+	}
+}
+
+func TestFillUint64sMatchesUint64(t *testing.T) {
+	for _, r := range []*RNG{New(3), mustWidth(t, 3, 512), mustSampling(t, 3, 64)} {
+		ref := r.Clone()
+		for _, n := range []int{0, 1, 3, 4, 9, 100, 1} { // unaligned starts too
+			got := make([]uint64, n)
+			r.FillUint64s(got)
+			for i, v := range got {
+				if want := ref.Uint64(); v != want {
+					t.Fatalf("len %d, index %d: %#x, want %#x", n, i, v, want)
+				}
+			}
+		}
+	}
+}
+
+func mustWidth(t *testing.T, seed uint64, width int) *RNG {
+	r, err := NewWithWidth(seed, width)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func mustSampling(t *testing.T, seed uint64, keep int) *RNG {
+	r, err := NewWithSampling(seed, keep)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestHotPathAllocs(t *testing.T) {
+	rng := New(42)
+	buf := make([]byte, 1<<10)
+	cases := []struct {
+		name string
+		fn   func()
+	}{
+		{"Read1KB", func() { rng.Read(buf) }},
+		{"ReadOdd", func() { rng.Read(buf[:7]) }},
+		{"Uint64", func() { rng.Uint64() }},
+		{"Uint32", func() { rng.Uint32() }},
+	}
+	for _, c := range cases {
+		if n := testing.AllocsPerRun(1000, c.fn); n != 0 {
+			t.Errorf("%s: %v allocs/op, want 0", c.name, n)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package rand
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// This file contains io.Reader wrappers around the deterministic stream.
+
+// contextChunk is how many bytes a context reader produces between checks
+// of its context
+const contextChunk = 32 << 10
+
+type contextReader struct {
+	ctx context.Context
+	rng *RNG
+}
+
+// NewContextReader returns a reader of the stream for seed that stops once
+// ctx is done
+// The context is checked before every 32KB chunk, so a cancelled Read
+// returns the bytes produced so far together with ctx.Err().
+func NewContextReader(ctx context.Context, seed uint64) io.Reader {
+	return &contextReader{ctx: ctx, rng: New(seed)}
+}
+
+func (c *contextReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if err := c.ctx.Err(); err != nil {
+			return n, err
+		}
+		end := min(n+contextChunk, len(p))
+		c.rng.Read(p[n:end])
+		n = end
+	}
+	return n, nil
+}
+
+// NewLimited returns a reader of exactly n bytes of the stream for seed,
+// after which it returns io.EOF
+func NewLimited(seed uint64, n int64) io.Reader {
+	return &io.LimitedReader{R: New(seed), N: n}
+}
+
+var _ io.ReaderAt = (*RNG)(nil)
+
+// ReadAt fills p with the stream starting at absolute offset off, without
+// advancing r
+// Offsets count from where r was last seeded (by its constructor, Reset,
+// Seed or SetState), so ReadAt(p, off) equals bytes off to off+len(p) of
+// the stream however much of it r has already read, as io.ReaderAt requires.
+// The stream is infinite, so ReadAt always fills p and never returns io.EOF.
+// It works on a copy of r, so concurrent ReadAt calls are safe as long as
+// nothing else is using r. Its cost grows with off, as for Skip.
+func (r *RNG) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("rand: negative offset")
+	}
+	c := r.Clone()
+	copy(c.state, c.origin)
+	c.gens = 0
+	c.pos = len(c.state)
+	c.buf = 0
+	c.nbuf = 0
+	c.left = 0
+	c.Skip(uint64(off))
+	return c.Read(p)
+}
+
+type seeker struct {
+	seed uint64
+	rng  *RNG
+	pos  int64
+}
+
+// NewSeeker returns a seekable reader of the stream for seed
+// Seeking forward skips ahead; seeking backward re-seeds and skips from the
+// start. io.SeekEnd is rejected because the stream has no end.
+func NewSeeker(seed uint64) io.ReadSeeker {
+	return &seeker{seed: seed, rng: New(seed)}
+}
+
+func (s *seeker) Read(p []byte) (int, error) {
+	n, err := s.rng.Read(p)
+	s.pos += int64(n)
+	return n, err
+}
+
+func (s *seeker) Seek(offset int64, whence int) (int64, error) {
+	var target int64
+	switch whence {
+	case io.SeekStart:
+		target = offset
+	case io.SeekCurrent:
+		target = s.pos + offset
+	case io.SeekEnd:
+		return s.pos, errors.New("rand: SeekEnd on an infinite stream")
+	default:
+		return s.pos, errors.New("rand: invalid whence")
+	}
+	if target < 0 {
+		return s.pos, errors.New("rand: negative position")
+	}
+
+	if target < s.pos {
+		s.rng.Reset(s.seed)
+		s.pos = 0
+	}
+	s.rng.Skip(uint64(target - s.pos))
+	s.pos = target
+	return target, nil
+}
+
+var _ io.WriterTo = (*RNG)(nil)
+
+// writeToChunk is the size of the chunks WriteTo hands to its writer, a whole
+// number of generations for every supported strip width
+const writeToChunk = 32 << 10
+
+// WriteTo writes the stream to w, implementing io.WriterTo
+// The stream never ends, so WriteTo only returns once w fails, reporting the
+// bytes written and w's error (io.ErrShortWrite for a short write). The first
+// write finishes any buffered bytes and the current generation; after that
+// every chunk starts on a generation boundary and is generated in place.
+// Bytes generated for a failed write are lost from the stream.
+func (r *RNG) WriteTo(w io.Writer) (int64, error) {
+	buf := make([]byte, writeToChunk)
+	var total int64
+	write := func(p []byte) error {
+		r.Read(p)
+		m, err := w.Write(p)
+		total += int64(m)
+		if err == nil && m < len(p) {
+			err = io.ErrShortWrite
+		}
+		return err
+	}
+
+	head := r.nbuf
+	if r.mode == modeMixed && r.pos < len(r.state) {
+		head += 8 * (len(r.state) - r.pos)
+	}
+	if head > 0 {
+		if err := write(buf[:head]); err != nil {
+			return total, err
+		}
+	}
+	for {
+		if err := write(buf); err != nil {
+			return total, err
+		}
+	}
+}
+
+// ensembleBlock is how many bytes an ensemble takes from each generator in
+// turn
+const ensembleBlock = 32
+
+type ensemble struct {
+	rngs []*RNG
+	cur  int // generator supplying the current block
+	off  int // bytes of the current block already read
+}
+
+// NewEnsemble returns a reader that takes 32-byte blocks from generators
+// seeded with seeds, round-robin in the order given
+// The output depends only on the seed list; a single seed gives the plain
+// stream of New. It panics if seeds is empty.
+func NewEnsemble(seeds []uint64) io.Reader {
+	if len(seeds) == 0 {
+		panic("rand: ensemble needs at least one seed")
+	}
+	e := &ensemble{rngs: make([]*RNG, len(seeds))}
+	for i, seed := range seeds {
+		e.rngs[i] = New(seed)
+	}
+	return e
+}
+
+func (e *ensemble) Read(p []byte) (int, error) {
+	for n := 0; n < len(p); {
+		k := min(ensembleBlock-e.off, len(p)-n)
+		e.rngs[e.cur].Read(p[n : n+k])
+		n += k
+		if e.off += k; e.off == ensembleBlock {
+			e.off = 0
+			e.cur = (e.cur + 1) % len(e.rngs)
+		}
+	}
+	return len(p), nil
+}
+
+type combinedXOR struct {
+	a, b *RNG
+}
+
+// NewCombinedXOR returns a reader of the streams for seedA and seedB XORed
+// together byte by byte
+// Combining independent streams hides the structure of either one. Byte i of
+// the output is byte i of New(seedA) XOR byte i of New(seedB).
+func NewCombinedXOR(seedA, seedB uint64) io.Reader {
+	return &combinedXOR{a: New(seedA), b: New(seedB)}
+}
+
+func (c *combinedXOR) Read(p []byte) (int, error) {
+	c.a.Read(p)
+	c.b.XORKeyStream(p, p)
+	return len(p), nil
+}
+
+type vonNeumann struct {
+	src  io.Reader
+	buf  [256]byte
+	in   []byte // unread part of buf
+	acc  uint16 // extracted bits not yet emitted, first bit lowest
+	nacc int
+}
+
+// NewVonNeumann returns a reader of the raw, unmixed strip of seed, debiased
+// with a von Neumann extractor
+// Each pair of input bits yields one output bit when the two differ (01 gives
+// 0, 10 gives 1) and nothing when they are equal, which removes any bias of
+// independent input bits. The output rate is therefore not constant: it
+// averages a quarter of the input bits for a balanced source and falls as
+// the bias grows. Bits are packed least significant bit first.
+func NewVonNeumann(seed uint64) io.Reader {
+	raw, _ := NewWithSampling(seed, DefaultWidth)
+	return newVonNeumann(raw)
+}
+
+// newVonNeumann applies the extractor to the bits of src
+func newVonNeumann(src io.Reader) *vonNeumann {
+	return &vonNeumann{src: src}
+}
+
+func (v *vonNeumann) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(v.in) == 0 {
+			m, err := v.src.Read(v.buf[:])
+			v.in = v.buf[:m]
+			if m == 0 && err != nil {
+				return n, err
+			}
+			continue
+		}
+
+		b := v.in[0]
+		v.in = v.in[1:]
+		for j := 0; j < 8; j += 2 {
+			if x := b >> j & 1; x != b>>(j+1)&1 {
+				v.acc |= uint16(x) << v.nacc
+				v.nacc++
+			}
+		}
+
+		// A byte adds at most 4 bits, so at most one output byte is ready
+		if v.nacc >= 8 {
+			p[n] = byte(v.acc)
+			n++
+			v.acc >>= 8
+			v.nacc -= 8
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,315 @@
+package rand
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/bits"
+	"testing"
+
+	"github.com/vrypan/r30r2/stats"
+)
+
+func TestContextReaderCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := NewContextReader(ctx, 77)
+
+	got := make([]byte, 3*contextChunk+5)
+	if n, err := r.Read(got); n != len(got) || err != nil {
+		t.Fatalf("Read = %d, %v; want %d, nil", n, err, len(got))
+	}
+	want := make([]byte, len(got))
+	New(77).Read(want)
+	if !bytes.Equal(got, want) {
+		t.Fatal("stream before cancellation differs from New")
+	}
+
+	cancel()
+	n, err := r.Read(make([]byte, 100))
+	if n != 0 || !errors.Is(err, context.Canceled) {
+		t.Errorf("Read after cancel = %d, %v; want 0, context.Canceled", n, err)
+	}
+}
+
+func TestLimited(t *testing.T) {
+	const n = 100003
+	r := NewLimited(5, n)
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := make([]byte, n)
+	New(5).Read(want)
+	if !bytes.Equal(got, want) {
+		t.Fatalf("got %d bytes, want the first %d bytes of the stream", len(got), n)
+	}
+	if m, err := r.Read(make([]byte, 1)); m != 0 || err != io.EOF {
+		t.Errorf("Read after limit = %d, %v; want 0, EOF", m, err)
+	}
+}
+
+func TestReadAtMatchesStream(t *testing.T) {
+	stream := make([]byte, 1<<16)
+	New(31).Read(stream)
+
+	rng := New(31)
+	src := New(99)
+	for range 500 {
+		off := src.Uint64N(uint64(len(stream)))
+		n := src.Uint64N(min(uint64(len(stream))-off, 300))
+		p := make([]byte, n)
+		if m, err := rng.ReadAt(p, int64(off)); m != len(p) || err != nil {
+			t.Fatalf("ReadAt(%d bytes, %d) = %d, %v", n, off, m, err)
+		}
+		if !bytes.Equal(p, stream[off:off+n]) {
+			t.Fatalf("ReadAt(%d bytes, %d) differs from the stream", n, off)
+		}
+	}
+
+	// Offsets stay absolute while the generator reads: interleaved Reads
+	// continue the stream and ReadAt still slices it from the start
+	pos := 0
+	for range 200 {
+		n := int(src.Uint64N(100))
+		got := make([]byte, n)
+		rng.Read(got)
+		if !bytes.Equal(got, stream[pos:pos+n]) {
+			t.Fatalf("Read at %d differs from the stream; ReadAt moved the generator", pos)
+		}
+		pos += n
+
+		off := src.Uint64N(uint64(len(stream) - 300))
+		p := make([]byte, src.Uint64N(300))
+		rng.ReadAt(p, int64(off))
+		if !bytes.Equal(p, stream[off:off+uint64(len(p))]) {
+			t.Fatalf("after reading %d bytes, ReadAt(%d bytes, %d) differs from the stream", pos, len(p), off)
+		}
+	}
+
+	// io.SectionReader relies on the absolute offsets
+	sec := io.NewSectionReader(rng, 1000, 500)
+	got, err := io.ReadAll(sec)
+	if err != nil || !bytes.Equal(got, stream[1000:1500]) {
+		t.Errorf("SectionReader returned %d bytes that differ from the stream (err %v)", len(got), err)
+	}
+
+	// Offsets count from the last reseed, including after a snapshot
+	data, _ := rng.MarshalBinary()
+	restored := &RNG{}
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	p := make([]byte, 64)
+	restored.ReadAt(p, 10)
+	if !bytes.Equal(p, stream[10:74]) {
+		t.Error("ReadAt offsets lost in snapshot round trip")
+	}
+	rng.Reset(32)
+	rng.Read(make([]byte, 100))
+	rng.ReadAt(p, 0)
+	if !bytes.Equal(p, New(32).Bytes(64)) {
+		t.Error("ReadAt does not count from Reset")
+	}
+
+	if _, err := rng.ReadAt(p, -1); err == nil {
+		t.Error("ReadAt accepted a negative offset")
+	}
+}
+
+func TestSeeker(t *testing.T) {
+	stream := make([]byte, 4096)
+	New(8).Read(stream)
+
+	s := NewSeeker(8)
+	prefix := make([]byte, 1000)
+	io.ReadFull(s, prefix)
+
+	if pos, err := s.Seek(0, io.SeekStart); pos != 0 || err != nil {
+		t.Fatalf("Seek(0, SeekStart) = %d, %v", pos, err)
+	}
+	again := make([]byte, 1000)
+	io.ReadFull(s, again)
+	if !bytes.Equal(again, prefix) || !bytes.Equal(prefix, stream[:1000]) {
+		t.Fatal("re-reading after seeking to 0 gave a different prefix")
+	}
+
+	// Forward and backward relative seeks
+	for _, off := range []int64{13, -500, 2000, -2511} {
+		pos, err := s.Seek(off, io.SeekCurrent)
+		if err != nil {
+			t.Fatalf("Seek(%d, SeekCurrent): %v", off, err)
+		}
+		p := make([]byte, 7)
+		io.ReadFull(s, p)
+		if !bytes.Equal(p, stream[pos:pos+7]) {
+			t.Fatalf("bytes at %d differ from the stream", pos)
+		}
+		s.Seek(-7, io.SeekCurrent)
+	}
+
+	if _, err := s.Seek(0, io.SeekEnd); err == nil {
+		t.Error("SeekEnd succeeded")
+	}
+	if _, err := s.Seek(-1, io.SeekStart); err == nil {
+		t.Error("seeking before the start succeeded")
+	}
+}
+
+// limitWriter collects the first n bytes written to it, then fails
+type limitWriter struct {
+	buf bytes.Buffer
+	n   int
+}
+
+var errLimit = errors.New("limit reached")
+
+func (l *limitWriter) Write(p []byte) (int, error) {
+	if room := l.n - l.buf.Len(); len(p) > room {
+		l.buf.Write(p[:room])
+		return room, errLimit
+	}
+	return l.buf.Write(p)
+}
+
+func TestWriteToMatchesRead(t *testing.T) {
+	const n = 3*writeToChunk + 1234
+	want := make([]byte, n+5)
+	New(3).Read(want)
+
+	// Start mid-word so the first write has to realign
+	rng := New(3)
+	rng.Read(make([]byte, 5))
+	w := &limitWriter{n: n}
+	written, err := rng.WriteTo(w)
+	if written != n || err != errLimit {
+		t.Fatalf("WriteTo = %d, %v; want %d, %v", written, err, n, errLimit)
+	}
+	if !bytes.Equal(w.buf.Bytes(), want[5:]) {
+		t.Error("WriteTo output differs from Read")
+	}
+}
+
+func TestEnsemble(t *testing.T) {
+	seeds := []uint64{1, 2, 3}
+	whole := make([]byte, 1000)
+	NewEnsemble(seeds).Read(whole)
+
+	// Reproducible, even when read in odd pieces
+	pieces := make([]byte, len(whole))
+	e := NewEnsemble(seeds)
+	for off := 0; off < len(pieces); off += 13 {
+		e.Read(pieces[off:min(off+13, len(pieces))])
+	}
+	if !bytes.Equal(pieces, whole) {
+		t.Fatal("ensemble output depends on read sizes")
+	}
+
+	// Block i comes from generator i%3
+	streams := [][]byte{New(1).Bytes(512), New(2).Bytes(512), New(3).Bytes(512)}
+	for i := 0; i*ensembleBlock < len(whole); i++ {
+		start := i * ensembleBlock
+		end := min(start+ensembleBlock, len(whole))
+		src := streams[i%3][i/3*ensembleBlock:]
+		if !bytes.Equal(whole[start:end], src[:end-start]) {
+			t.Fatalf("block %d does not come from generator %d", i, i%3)
+		}
+	}
+
+	single := make([]byte, 1000)
+	NewEnsemble([]uint64{9}).Read(single)
+	if !bytes.Equal(single, New(9).Bytes(1000)) {
+		t.Error("single-seed ensemble differs from New")
+	}
+}
+
+func TestCombinedXOR(t *testing.T) {
+	got := make([]byte, 1<<16)
+	c := NewCombinedXOR(10, 20)
+	for off := 0; off < len(got); off += 1000 {
+		c.Read(got[off:min(off+1000, len(got))])
+	}
+
+	a, b := New(10).Bytes(len(got)), New(20).Bytes(len(got))
+	for i := range got {
+		if got[i] != a[i]^b[i] {
+			t.Fatalf("byte %d: got %#x, want %#x ^ %#x", i, got[i], a[i], b[i])
+		}
+	}
+
+	if p, passed := stats.MonobitTest(got); !passed {
+		t.Errorf("combined stream failed the monobit test (p = %g)", p)
+	}
+}
+
+// biasedReader yields bits that are 1 with probability p1
+type biasedReader struct {
+	rng *RNG
+	p1  float64
+}
+
+func (b *biasedReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+		for j := range 8 {
+			if b.rng.Float64() < b.p1 {
+				p[i] |= 1 << j
+			}
+		}
+	}
+	return len(p), nil
+}
+
+func onesFraction(p []byte) float64 {
+	ones := 0
+	for _, b := range p {
+		ones += bits.OnesCount8(b)
+	}
+	return float64(ones) / float64(8*len(p))
+}
+
+func TestVonNeumannDebiases(t *testing.T) {
+	biased := make([]byte, 10000)
+	(&biasedReader{rng: New(1), p1: 0.8}).Read(biased)
+	if f := onesFraction(biased); f < 0.78 || f > 0.82 {
+		t.Fatalf("synthetic source has %.4f ones, want about 0.8", f)
+	}
+
+	out := make([]byte, 10000)
+	if _, err := io.ReadFull(newVonNeumann(&biasedReader{rng: New(1), p1: 0.8}), out); err != nil {
+		t.Fatal(err)
+	}
+	if f := onesFraction(out); f < 0.49 || f > 0.51 {
+		t.Errorf("extracted bits have %.4f ones, want about 0.5", f)
+	}
+}
+
+func TestVonNeumannDeterministic(t *testing.T) {
+	want := make([]byte, 4096)
+	NewVonNeumann(9).Read(want)
+
+	// Odd read sizes must not lose or repeat bits
+	got := make([]byte, len(want))
+	r := NewVonNeumann(9)
+	for off := 0; off < len(got); off += 37 {
+		r.Read(got[off:min(off+37, len(got))])
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("split reads differ from one read")
+	}
+	if bytes.Equal(want, New(9).Bytes(len(want))) {
+		t.Error("extractor output equals the plain stream")
+	}
+	if p, passed := stats.MonobitTest(want); !passed {
+		t.Errorf("extracted stream failed the monobit test (p = %g)", p)
+	}
+}
+
+func TestVonNeumannSourceError(t *testing.T) {
+	// 0x01 has one 10 pair, so 16 input bytes make two output bytes
+	src := bytes.NewReader(bytes.Repeat([]byte{0x01}, 16))
+	got, err := io.ReadAll(newVonNeumann(src))
+	if err != nil || !bytes.Equal(got, []byte{0xff, 0xff}) {
+		t.Errorf("got %x, %v; want ffff, nil", got, err)
+	}
+}
@@ -0,0 +1,116 @@
+package rand
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// This file contains support for running the strip with an arbitrary
+// elementary CA rule instead of the built-in radius-2 Rule 30 variant.
+
+// RuleR30R2 is the rule number of the built-in radius-2 rule
+// new_bit = (left2 XOR left1) XOR ((center OR right1) OR right2)
+const RuleR30R2 uint64 = 0xFE0101FE
+
+// MaxRadius is the widest neighborhood NewWithRuleTable accepts
+// A radius-r rule table has 2^(2r+1) entries, so radius 3 needs a 128-bit
+// rule number and only NewWithRuleTable can express it.
+const MaxRadius = 3
+
+// NewWithRule creates a new RNG that evolves the 256-bit strip with a custom
+// rule instead of the built-in one
+// rule is a Wolfram rule number: bit i gives the new cell value for the
+// neighborhood whose cells, read left to right, spell i in binary. Radius 1
+// takes 8-bit rules (rule 30 is the classic Rule 30), radius 2 takes 32-bit
+// rules (RuleR30R2 reproduces New). Output mixing is unchanged. Radius-3
+// rules do not fit in a uint64; use NewWithRuleTable for them.
+func NewWithRule(seed uint64, radius int, rule uint64) (*RNG, error) {
+	if radius == 3 {
+		return nil, fmt.Errorf("rand: radius-3 rules have 128 bits; use NewWithRuleTable")
+	}
+	if err := checkRule(radius, rule, 0); err != nil {
+		return nil, err
+	}
+	rng := New(seed)
+	rng.radius = radius
+	rng.rule = rule
+	return rng, nil
+}
+
+// NewWithRuleTable is NewWithRule with the rule number given as a
+// little-endian table: bit i of the rule is bit i%8 of table[i/8]
+// The table holds exactly 2^(2*radius+1) bits: 1 byte for radius 1, 4 for
+// radius 2 and 16 for radius 3, the only way to give a 128-bit radius-3
+// rule.
+func NewWithRuleTable(seed uint64, radius int, table []byte) (*RNG, error) {
+	if radius < 1 || radius > MaxRadius {
+		return nil, fmt.Errorf("rand: unsupported radius %d (want 1 to %d)", radius, MaxRadius)
+	}
+	if want := (1 << (2*radius + 1)) / 8; len(table) != want {
+		return nil, fmt.Errorf("rand: radius-%d rule table has %d bytes, want %d", radius, len(table), want)
+	}
+	var rule [2]uint64
+	for i, b := range table {
+		rule[i/8] |= uint64(b) << (8 * (i % 8))
+	}
+	rng := New(seed)
+	rng.radius = radius
+	rng.rule, rng.ruleHi = rule[0], rule[1]
+	return rng, nil
+}
+
+// checkRule validates that the rule with low and high words rule and ruleHi
+// fits the table size for radius
+func checkRule(radius int, rule, ruleHi uint64) error {
+	if radius < 1 || radius > MaxRadius {
+		return fmt.Errorf("rand: unsupported radius %d (want 1 to %d)", radius, MaxRadius)
+	}
+	entries := uint(1) << (2*radius + 1)
+	if entries < 128 && ruleHi != 0 || entries < 64 && rule>>entries != 0 {
+		return fmt.Errorf("rand: rule %s does not fit a radius-%d table (%d bits)", ruleString(rule, ruleHi), radius, entries)
+	}
+	return nil
+}
+
+// ruleString formats a rule number of up to 128 bits in decimal
+func ruleString(rule, ruleHi uint64) string {
+	if ruleHi == 0 {
+		return fmt.Sprint(rule)
+	}
+	n := new(big.Int).SetUint64(ruleHi)
+	n.Lsh(n, 64)
+	return n.Or(n, new(big.Int).SetUint64(rule)).String()
+}
+
+// applyRule computes the next value of word cur under a custom rule,
+// given its neighbouring words prev and next
+// All 64 cells are evaluated in parallel.
+func (r *RNG) applyRule(prev, cur, next uint64) uint64 {
+	// Neighborhood words from leftmost to rightmost cell
+	var nb [2*MaxRadius + 1]uint64
+	n := 2*r.radius + 1
+	nb[r.radius] = cur
+	for k := 1; k <= r.radius; k++ {
+		nb[r.radius-k] = (cur >> k) | (prev << (64 - k))
+		nb[r.radius+k] = (cur << k) | (next >> (64 - k))
+	}
+	if n == 7 {
+		// A 128-entry table: the leftmost cell picks the high or low word
+		lo := ruleTable(r.rule, nb[1:n])
+		hi := ruleTable(r.ruleHi, nb[1:n])
+		return (lo &^ nb[0]) | (hi & nb[0])
+	}
+	return ruleTable(r.rule, nb[:n])
+}
+
+// ruleTable evaluates a rule table bit-parallel as a tree of multiplexers
+// nb[0] selects the most significant bit of the table index.
+func ruleTable(rule uint64, nb []uint64) uint64 {
+	if len(nb) == 0 {
+		return -(rule & 1) // all ones if the entry is set
+	}
+	half := uint(1) << (len(nb) - 1)
+	lo := ruleTable(rule&(1<<half-1), nb[1:])
+	hi := ruleTable(rule>>half, nb[1:])
+	return (lo &^ nb[0]) | (hi & nb[0])
+}
@@ -0,0 +1,196 @@
+package rand
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// cell returns cell c of the strip (cell 0 is the top bit of word 0)
+func cell(r *RNG, c int) bool {
+	return r.state[c/64]>>(63-c%64)&1 == 1
+}
+
+// impulse clears the strip and sets the single cell c
+func impulse(r *RNG, c int) {
+	for i := range r.state {
+		r.state[i] = 0
+	}
+	r.state[c/64] = 1 << (63 - c%64)
+}
+
+func TestRule30Triangle(t *testing.T) {
+	// Canonical Rule 30 evolution from a single black cell
+	triangle := []string{
+		"1",
+		"111",
+		"11001",
+		"1101111",
+		"110010001",
+		"11011110111",
+		"1100100001001",
+		"110111100111111",
+		"11001000111000001",
+	}
+
+	r, err := NewWithRule(0, 1, 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const center = 128
+	impulse(r, center)
+
+	for gen, row := range triangle {
+		var sb strings.Builder
+		for c := center - gen; c <= center+gen; c++ {
+			if cell(r, c) {
+				sb.WriteByte('1')
+			} else {
+				sb.WriteByte('0')
+			}
+		}
+		if sb.String() != row {
+			t.Fatalf("generation %d: got %s, want %s", gen, sb.String(), row)
+		}
+		r.step()
+	}
+}
+
+func TestRuleR30R2MatchesNew(t *testing.T) {
+	r, err := NewWithRule(31415, 2, RuleR30R2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, want := make([]byte, 4096), make([]byte, 4096)
+	r.Read(got)
+	New(31415).Read(want)
+	if !bytes.Equal(got, want) {
+		t.Error("custom rule RuleR30R2 differs from the built-in rule")
+	}
+}
+
+func TestNewWithRuleValidation(t *testing.T) {
+	for _, tc := range []struct {
+		radius int
+		rule   uint64
+		ok     bool
+	}{
+		{1, 30, true},
+		{1, 255, true},
+		{1, 256, false},
+		{2, 1<<32 - 1, true},
+		{2, 1 << 32, false},
+		{0, 30, false},
+		{3, 30, false},
+	} {
+		_, err := NewWithRule(1, tc.radius, tc.rule)
+		if (err == nil) != tc.ok {
+			t.Errorf("NewWithRule(radius=%d, rule=%d): err = %v", tc.radius, tc.rule, err)
+		}
+	}
+}
+
+// radius3Table widens a radius-2 rule to radius 3 by ignoring the outermost
+// cells of the neighborhood
+func radius3Table(rule uint64) []byte {
+	table := make([]byte, 16)
+	for i := range 128 {
+		if rule>>((i>>1)&31)&1 == 1 {
+			table[i/8] |= 1 << (i % 8)
+		}
+	}
+	return table
+}
+
+func TestRadius3Rule(t *testing.T) {
+	r, err := NewWithRuleTable(31415, 3, radius3Table(RuleR30R2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, want := make([]byte, 4096), make([]byte, 4096)
+	r.Read(got)
+	New(31415).Read(want)
+	if !bytes.Equal(got, want) {
+		t.Error("radius-3 table ignoring the outer cells differs from the built-in rule")
+	}
+
+	// The upper 64 entries are the neighborhoods whose leftmost cell is
+	// set, so this rule copies the cell three to the left
+	table := make([]byte, 16)
+	for i := 8; i < 16; i++ {
+		table[i] = 0xff
+	}
+	shift, err := NewWithRuleTable(5, 3, table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := shift.CopyState()
+	after := shift.StepGeneration()
+	for c := range 256 {
+		src := (c - 3 + 256) % 256
+		if after[c/64]>>(63-c%64)&1 != before[src/64]>>(63-src%64)&1 {
+			t.Fatalf("cell %d is not a copy of cell %d", c, src)
+		}
+	}
+	if want := "rule=340282366920938463444927863358058659840"; !strings.Contains(shift.Describe(), want) {
+		t.Errorf("Describe() = %q, want %s", shift.Describe(), want)
+	}
+}
+
+func TestNewWithRuleTableValidation(t *testing.T) {
+	for _, tc := range []struct {
+		radius int
+		size   int
+		ok     bool
+	}{
+		{1, 1, true},
+		{2, 4, true},
+		{3, 16, true},
+		{3, 8, false},
+		{2, 16, false},
+		{4, 256, false},
+		{0, 1, false},
+	} {
+		_, err := NewWithRuleTable(1, tc.radius, make([]byte, tc.size))
+		if (err == nil) != tc.ok {
+			t.Errorf("NewWithRuleTable(radius=%d, %d bytes): err = %v", tc.radius, tc.size, err)
+		}
+	}
+
+	// Smaller radii match NewWithRule
+	a, _ := NewWithRuleTable(9, 1, []byte{30})
+	b, _ := NewWithRule(9, 1, 30)
+	if !bytes.Equal(readN(a, 256), readN(b, 256)) {
+		t.Error("radius-1 table differs from NewWithRule")
+	}
+}
+
+func TestMarshalCustomRule(t *testing.T) {
+	orig, _ := NewWithRule(77, 1, 30)
+	orig.Read(make([]byte, 9))
+
+	data, _ := orig.MarshalBinary()
+	restored := &RNG{}
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+
+	want, got := make([]byte, 1024), make([]byte, 1024)
+	orig.Read(want)
+	restored.Read(got)
+	if !bytes.Equal(got, want) {
+		t.Error("custom rule lost in snapshot round trip")
+	}
+
+	// The high word of a radius-3 rule survives too
+	wide, _ := NewWithRuleTable(77, 3, radius3Table(0x96696996))
+	wide.Read(make([]byte, 9))
+	data, _ = wide.MarshalBinary()
+	restored = &RNG{}
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(readN(restored, 1024), readN(wide, 1024)) {
+		t.Error("radius-3 rule lost in snapshot round trip")
+	}
+}
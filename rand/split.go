@@ -0,0 +1,52 @@
+package rand
+
+// Split derives a new RNG whose stream is decorrelated from r's
+// The child has the same width as r. Its strip is built from the next
+// width/64 Uint64 values of r passed through a 64-bit finalizer, so repeated
+// calls yield different children; that is exactly one generation of r only
+// when r sits at a generation boundary and uses the default mixed output.
+// The result depends only on r's state, which keeps parallel runs
+// reproducible from a single seed.
+// Only the width is inherited: the child always runs the built-in rule with
+// mixed output, the default bit order and no warmup, whatever r uses.
+func (r *RNG) Split() *RNG {
+	child := &RNG{state: make([]uint64, len(r.state))}
+	for i := range child.state {
+		child.state[i] = splitMix(r.Uint64())
+	}
+	child.pos = len(child.state)
+	if child.empty() {
+		// An empty strip never changes; this is astronomically unlikely
+		child.init(0)
+	}
+	child.markOrigin()
+	return child
+}
+
+// SplitN derives n independent RNGs from r, in the same order as n calls to
+// Split would
+func (r *RNG) SplitN(n int) []*RNG {
+	children := make([]*RNG, n)
+	for i := range children {
+		children[i] = r.Split()
+	}
+	return children
+}
+
+// StreamAt returns New(seed) already advanced to byteOffset
+// Worker k of a job can call StreamAt(seed, k*chunk) and read chunk bytes;
+// concatenating the workers' output in order gives the single stream of
+// New(seed). Positioning uses Skip, which evolves the strip without producing
+// output, so its cost still grows with byteOffset.
+func StreamAt(seed uint64, byteOffset uint64) *RNG {
+	r := New(seed)
+	r.Skip(byteOffset)
+	return r
+}
+
+// splitMix is the SplitMix64 finalizer, a bijective 64-bit hash
+func splitMix(x uint64) uint64 {
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
@@ -0,0 +1,72 @@
+package rand
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+// correlation returns the Pearson correlation of two equal-length byte streams
+func correlation(a, b []byte) float64 {
+	var sa, sb, saa, sbb, sab float64
+	for i := range a {
+		x, y := float64(a[i]), float64(b[i])
+		sa += x
+		sb += y
+		saa += x * x
+		sbb += y * y
+		sab += x * y
+	}
+	n := float64(len(a))
+	return (n*sab - sa*sb) / math.Sqrt((n*saa-sa*sa)*(n*sbb-sb*sb))
+}
+
+func TestSplitUncorrelated(t *testing.T) {
+	parent := New(12345)
+	children := parent.SplitN(2)
+
+	const size = 1 << 20
+	p, a, b := make([]byte, size), make([]byte, size), make([]byte, size)
+	parent.Read(p)
+	children[0].Read(a)
+	children[1].Read(b)
+
+	// Standard error of the correlation is ~1/sqrt(size) ≈ 0.001
+	for name, c := range map[string]float64{
+		"child/child":  correlation(a, b),
+		"parent/child": correlation(p, a),
+	} {
+		if math.Abs(c) > 0.005 {
+			t.Errorf("%s correlation = %.5f", name, c)
+		}
+	}
+}
+
+func TestSplitDeterministic(t *testing.T) {
+	a := New(99).SplitN(3)
+	b := New(99).SplitN(3)
+	for i := range a {
+		x, y := make([]byte, 256), make([]byte, 256)
+		a[i].Read(x)
+		b[i].Read(y)
+		if !bytes.Equal(x, y) {
+			t.Errorf("child %d differs between identically seeded parents", i)
+		}
+	}
+}
+
+func TestStreamAtReconstructsStream(t *testing.T) {
+	const chunk = 10007 // deliberately not a whole number of generations
+	want := make([]byte, 3*chunk)
+	New(2024).Read(want)
+
+	var got []byte
+	for k := range uint64(3) {
+		part := make([]byte, chunk)
+		StreamAt(2024, k*chunk).Read(part)
+		got = append(got, part...)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("StreamAt chunks do not reconstruct the stream")
+	}
+}
@@ -0,0 +1,207 @@
+package rand
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// This file contains methods for snapshotting and restoring the exact
+// position of an RNG in its stream.
+
+// stateVersion identifies the layout written by MarshalBinary
+// Bump it whenever the serialized layout changes.
+// Version 2 added the word count for configurable strip widths.
+// Version 3 added the custom rule radius and number.
+// Version 4 added the output mode.
+// Version 5 added the sampling position.
+// Version 6 added the warmup length.
+// Version 7 added the generation counter.
+// Version 8 added the cell mask.
+// Version 9 added the output bit order.
+// Version 10 added the high word of radius-3 rules.
+// Version 11 added the strip at the last (re)seed.
+const stateVersion = 11
+
+// stateSize returns the length of a snapshot of a strip with n words:
+// version (1) + words (1) + radius (1) + rule (8) + mode (1) + order (1) +
+// keep (2) + left (2) + warmup (4) + gens (8) + strip (8n) + mask (8n) +
+// pos (1) + nbuf (1) + buf (8) + rule high word (8) + origin (8n)
+func stateSize(n int) int {
+	return 1 + 1 + 1 + 8 + 1 + 1 + 2 + 2 + 4 + 8 + 8*n + 8*n + 1 + 1 + 8 + 8 + 8*n
+}
+
+// Clone returns an independent copy of r at its current position
+// The copy produces exactly the bytes r would produce next; reading from one
+// does not affect the other.
+func (r *RNG) Clone() *RNG {
+	c := *r
+	c.state = append([]uint64(nil), r.state...)
+	c.origin = append([]uint64(nil), r.origin...)
+	return &c
+}
+
+// CopyState returns a copy of the raw strip, one word per 64 cells
+// Cell c is bit 63-c%64 of word c/64. The copy does not include the output
+// position or buffered bytes.
+func (r *RNG) CopyState() []uint64 {
+	return append([]uint64(nil), r.state...)
+}
+
+// SetState installs a raw strip, as returned by CopyState, and discards any
+// buffered output so the next output comes from the following generation
+// This bypasses seeding entirely: no seed pattern or warmup is applied and the
+// generation counter restarts at zero. state must have one word per 64 cells
+// of r's strip. An all-zero strip is accepted but only ever produces zeros.
+func (r *RNG) SetState(state []uint64) error {
+	if len(state) != len(r.state) {
+		return fmt.Errorf("rand: state has %d words, want %d", len(state), len(r.state))
+	}
+	copy(r.state, state)
+	r.gens = 0
+	r.pos = len(r.state)
+	r.buf = 0
+	r.nbuf = 0
+	r.left = 0
+	r.markOrigin()
+	return nil
+}
+
+// State is a complete in-memory snapshot of an RNG, taken by FullState
+// Unlike CopyState it includes the output position and buffered bytes. Use
+// MarshalBinary instead to persist a snapshot.
+type State struct {
+	rng RNG
+}
+
+// FullState returns a snapshot of everything that determines r's future
+// output
+func (r *RNG) FullState() State {
+	return State{rng: *r.Clone()}
+}
+
+// RestoreState puts r in the exact position captured by s, including any
+// partially consumed output word
+// s can be restored any number of times and into any RNG.
+func (r *RNG) RestoreState(s State) {
+	*r = *s.rng.Clone()
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+// The snapshot holds the strip, the word position and any buffered bytes,
+// so an RNG restored from it continues the stream byte-for-byte.
+// encoding/gob uses it too, so an *RNG can be a field of gob-encoded structs.
+func (r *RNG) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, stateSize(len(r.state)))
+	data = append(data, stateVersion, byte(len(r.state)), byte(r.radius))
+	data = binary.LittleEndian.AppendUint64(data, r.rule)
+	var order byte
+	if r.msbFirst {
+		order = 1
+	}
+	data = append(data, byte(r.mode), order)
+	data = binary.LittleEndian.AppendUint16(data, uint16(r.keep))
+	data = binary.LittleEndian.AppendUint16(data, uint16(r.left))
+	data = binary.LittleEndian.AppendUint32(data, uint32(r.warmup))
+	data = binary.LittleEndian.AppendUint64(data, r.gens)
+	for _, w := range r.state {
+		data = binary.LittleEndian.AppendUint64(data, w)
+	}
+	mask := make([]uint64, len(r.state))
+	for _, c := range r.cells {
+		mask[c/64] |= 1 << (63 - c%64)
+	}
+	for _, w := range mask {
+		data = binary.LittleEndian.AppendUint64(data, w)
+	}
+	data = append(data, byte(r.pos), byte(r.nbuf))
+	data = binary.LittleEndian.AppendUint64(data, r.buf)
+	data = binary.LittleEndian.AppendUint64(data, r.ruleHi)
+	for _, w := range r.origin {
+		data = binary.LittleEndian.AppendUint64(data, w)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+// Snapshots written with a different layout version are rejected.
+func (r *RNG) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return errors.New("rand: empty state")
+	}
+	if data[0] != stateVersion {
+		return fmt.Errorf("rand: unsupported state version %d (want %d)", data[0], stateVersion)
+	}
+	if len(data) < 3 {
+		return errors.New("rand: truncated state")
+	}
+	n := int(data[1])
+	if len(data) != stateSize(n) {
+		return fmt.Errorf("rand: state is %d bytes, want %d", len(data), stateSize(n))
+	}
+
+	radius := int(data[2])
+	rule := binary.LittleEndian.Uint64(data[3:])
+	ruleHi := binary.LittleEndian.Uint64(data[len(data)-8*n-8:])
+	origin := make([]uint64, n)
+	for i := range origin {
+		origin[i] = binary.LittleEndian.Uint64(data[len(data)-8*n+8*i:])
+	}
+	mode := outputMode(data[11])
+	order := data[12]
+	keep := int(binary.LittleEndian.Uint16(data[13:]))
+	left := int(binary.LittleEndian.Uint16(data[15:]))
+	warmup := int(binary.LittleEndian.Uint32(data[17:]))
+	gens := binary.LittleEndian.Uint64(data[21:])
+	words := data[29:]
+	mask := make([]uint64, n)
+	for i := range mask {
+		mask[i] = binary.LittleEndian.Uint64(words[8*(n+i):])
+	}
+	tail := words[16*n:]
+	pos, nbuf := int(tail[0]), int(tail[1])
+	if n < 2 || pos > n || nbuf > 7 || !mode.valid() || order > 1 || left > keep || warmup > math.MaxInt32 {
+		return errors.New("rand: corrupt state")
+	}
+	if mode == modeSample && (keep < 1 || n*64%keep != 0) {
+		return errors.New("rand: corrupt state")
+	}
+	cells := maskCells(mask)
+	if (mode == modeMask) != (len(cells) > 0) || (mode == modeMask && keep != len(cells)) {
+		return errors.New("rand: corrupt state")
+	}
+	if len(cells) == 0 {
+		cells = nil
+	}
+	if radius == 0 && ruleHi != 0 {
+		return errors.New("rand: corrupt state")
+	}
+	if radius != 0 {
+		if err := checkRule(radius, rule, ruleHi); err != nil {
+			return fmt.Errorf("rand: corrupt state: %w", err)
+		}
+	}
+
+	if len(r.state) != n {
+		r.state = make([]uint64, n)
+	}
+	for i := range r.state {
+		r.state[i] = binary.LittleEndian.Uint64(words[i*8:])
+	}
+	r.radius = radius
+	r.rule = rule
+	r.ruleHi = ruleHi
+	r.origin = origin
+	r.mode = mode
+	r.msbFirst = order == 1
+	r.keep = keep
+	r.left = left
+	r.cells = cells
+	r.warmup = warmup
+	r.gens = gens
+	r.pos = pos
+	r.nbuf = nbuf
+	r.buf = binary.LittleEndian.Uint64(tail[2:])
+	return nil
+}
@@ -0,0 +1,167 @@
+package rand
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"math"
+	"testing"
+)
+
+func TestMarshalRoundTrip(t *testing.T) {
+	orig := New(2718)
+	orig.Read(make([]byte, 1234567)) // stop mid-word
+
+	data, err := orig.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := &RNG{}
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+
+	want, got := make([]byte, 1<<20), make([]byte, 1<<20)
+	orig.Read(want)
+	restored.Read(got)
+	if !bytes.Equal(got, want) {
+		t.Error("restored RNG diverges from the original")
+	}
+}
+
+func TestUnmarshalRejectsBadState(t *testing.T) {
+	data, _ := New(1).MarshalBinary()
+
+	bad := append([]byte(nil), data...)
+	bad[0] = stateVersion + 1
+	if err := new(RNG).UnmarshalBinary(bad); err == nil {
+		t.Error("unknown version accepted")
+	}
+	if err := new(RNG).UnmarshalBinary(data[:10]); err == nil {
+		t.Error("truncated state accepted")
+	}
+	if err := new(RNG).UnmarshalBinary(nil); err == nil {
+		t.Error("empty state accepted")
+	}
+
+	// A warmup with the top bit set is what a negative int would save as
+	bad = append([]byte(nil), data...)
+	binary.LittleEndian.PutUint32(bad[17:], math.MaxUint32)
+	if err := new(RNG).UnmarshalBinary(bad); err == nil || err.Error() != "rand: corrupt state" {
+		t.Errorf("warmup 2^32-1: err = %v, want rand: corrupt state", err)
+	}
+}
+
+func TestGobRoundTrip(t *testing.T) {
+	type checkpoint struct {
+		Step int
+		RNG  *RNG
+	}
+
+	in := checkpoint{Step: 42, RNG: New(161803)}
+	in.RNG.Read(make([]byte, 77))
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&in); err != nil {
+		t.Fatal(err)
+	}
+	var out checkpoint
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	want, got := make([]byte, 4096), make([]byte, 4096)
+	in.RNG.Read(want)
+	out.RNG.Read(got)
+	if out.Step != 42 || !bytes.Equal(got, want) {
+		t.Error("gob round trip lost the stream position")
+	}
+}
+
+func TestCloneIndependent(t *testing.T) {
+	orig := New(1414)
+	orig.Read(make([]byte, 13))
+
+	ref := orig.Clone()
+	want := make([]byte, 500)
+	ref.Read(want)
+
+	clone := orig.Clone()
+	a := make([]byte, 200)
+	clone.Read(a)
+	clone.Read(make([]byte, 1000)) // run the clone far ahead
+
+	b := make([]byte, 300)
+	orig.Read(b[:123])
+	orig.Read(b[123:])
+
+	if !bytes.Equal(a, want[:200]) {
+		t.Error("clone does not continue the original stream")
+	}
+	if !bytes.Equal(b, want[:300]) {
+		t.Error("reading from the clone disturbed the original")
+	}
+}
+
+func TestMarshalWideStrip(t *testing.T) {
+	orig, _ := NewWithWidth(55, 512)
+	orig.Read(make([]byte, 101))
+
+	data, _ := orig.MarshalBinary()
+	restored := New(1) // different width; must be replaced
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+
+	want, got := make([]byte, 4096), make([]byte, 4096)
+	orig.Read(want)
+	restored.Read(got)
+	if !bytes.Equal(got, want) {
+		t.Error("restored 512-bit RNG diverges from the original")
+	}
+}
+
+func TestSetStateRestoresCopyState(t *testing.T) {
+	r := New(31415)
+	r.Read(make([]byte, 4096)) // a whole number of generations
+
+	saved := r.CopyState()
+	first := make([]byte, 1024)
+	r.Read(first)
+
+	if err := r.SetState(saved); err != nil {
+		t.Fatal(err)
+	}
+	second := make([]byte, 1024)
+	r.Read(second)
+	if !bytes.Equal(first, second) {
+		t.Error("stream after SetState differs from the stream after CopyState")
+	}
+
+	if err := r.SetState(saved[:2]); err == nil {
+		t.Error("state of the wrong width accepted")
+	}
+}
+
+func TestRestoreStateAfterOddReads(t *testing.T) {
+	for _, sizes := range [][]int{{5}, {1, 2, 3}, {33}, {7, 100, 1}, {8, 5}} {
+		r := New(27182)
+		for _, n := range sizes {
+			r.Read(make([]byte, n))
+		}
+
+		s := r.FullState()
+		want := make([]byte, 1000)
+		r.Read(want)
+
+		for range 2 {
+			restored := new(RNG)
+			restored.RestoreState(s)
+			got := make([]byte, 1000)
+			restored.Read(got)
+			if !bytes.Equal(got, want) {
+				t.Errorf("reads %v: restored stream differs", sizes)
+			}
+		}
+	}
+}
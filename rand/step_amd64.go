@@ -0,0 +1,18 @@
+//go:build amd64 && !purego
+
+// This file contains the AVX2 fast path for evolving a 256-bit strip
+
+package rand
+
+import "golang.org/x/sys/cpu"
+
+// useAVX2 selects stepAVX2 for the default 4-word strip
+// cpu.X86.HasAVX2 also checks that the OS saves the YMM registers. It is a
+// variable so tests and benchmarks can force the pure-Go path.
+var useAVX2 = cpu.X86.HasAVX2
+
+// stepAVX2 applies one generation of the radius-2 rule to a 4-word strip
+// The whole strip is held in a single YMM register.
+//
+//go:noescape
+func stepAVX2(s *[4]uint64)
@@ -0,0 +1,36 @@
+//go:build amd64 && !purego
+
+#include "textflag.h"
+
+// func stepAVX2(s *[4]uint64)
+TEXT ·stepAVX2(SB), NOSPLIT, $0-8
+	MOVQ s+0(FP), AX
+	VMOVDQU (AX), Y0
+
+	// Y1 holds the previous word of each lane, Y2 the next (circular)
+	VPERMQ $0x93, Y0, Y1
+	VPERMQ $0x39, Y0, Y2
+
+	// left2 ^ left1
+	VPSRLQ $2, Y0, Y3
+	VPSLLQ $62, Y1, Y4
+	VPOR   Y4, Y3, Y3
+	VPSRLQ $1, Y0, Y4
+	VPSLLQ $63, Y1, Y5
+	VPOR   Y5, Y4, Y4
+	VPXOR  Y4, Y3, Y3
+
+	// center | right1 | right2
+	VPSLLQ $1, Y0, Y4
+	VPSRLQ $63, Y2, Y5
+	VPOR   Y5, Y4, Y4
+	VPSLLQ $2, Y0, Y5
+	VPSRLQ $62, Y2, Y6
+	VPOR   Y6, Y5, Y5
+	VPOR   Y0, Y4, Y4
+	VPOR   Y5, Y4, Y4
+
+	VPXOR   Y4, Y3, Y3
+	VMOVDQU Y3, (AX)
+	VZEROUPPER
+	RET
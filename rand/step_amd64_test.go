@@ -0,0 +1,54 @@
+//go:build amd64 && !purego
+
+package rand
+
+import (
+	"bytes"
+	"testing"
+)
+
+// withAVX2 runs fn with the fast path forced on or off
+func withAVX2(t testing.TB, on bool, fn func()) {
+	t.Helper()
+	saved := useAVX2
+	useAVX2 = on
+	defer func() { useAVX2 = saved }()
+	fn()
+}
+
+func TestStepAVX2MatchesGeneric(t *testing.T) {
+	if !useAVX2 {
+		t.Skip("AVX2 not available")
+	}
+	for _, seed := range []uint64{0, 1, 42, 0xdeadbeefcafebabe} {
+		var fast, slow []byte
+		withAVX2(t, true, func() {
+			fast = make([]byte, 1<<20)
+			New(seed).Read(fast)
+		})
+		withAVX2(t, false, func() {
+			slow = make([]byte, 1<<20)
+			New(seed).Read(slow)
+		})
+		if !bytes.Equal(fast, slow) {
+			t.Fatalf("seed %d: AVX2 and pure-Go streams differ", seed)
+		}
+	}
+}
+
+func benchmarkStepAVX2(b *testing.B, on bool) {
+	if on && !useAVX2 {
+		b.Skip("AVX2 not available")
+	}
+	withAVX2(b, on, func() {
+		rng := New(42)
+		b.SetBytes(DefaultWidth / 8)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			rng.step()
+		}
+	})
+}
+
+func BenchmarkR30R2_Step_AVX2(b *testing.B)    { benchmarkStepAVX2(b, true) }
+func BenchmarkR30R2_Step_Generic(b *testing.B) { benchmarkStepAVX2(b, false) }
@@ -0,0 +1,11 @@
+//go:build !amd64 || purego
+
+// This file contains the pure-Go fallback used where no vector fast path exists
+
+package rand
+
+const useAVX2 = false
+
+func stepAVX2(s *[4]uint64) {
+	panic("rand: stepAVX2 not available")
+}
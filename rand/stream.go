@@ -0,0 +1,45 @@
+package rand
+
+import "crypto/cipher"
+
+// This file contains keystream helpers for using the generator as a toy
+// stream cipher.
+//
+// WARNING: R30R2 is NOT cryptographically secure. The strip can be recovered
+// from its output, so anything "encrypted" this way offers no secrecy. Use it
+// for tests, obfuscation experiments and teaching, never to protect data.
+
+var _ cipher.Stream = (*RNG)(nil)
+
+// NewStream returns a cipher.Stream whose keystream is the R30R2 stream
+// for seed
+// Consecutive XORKeyStream calls continue the keystream, so encrypting a
+// buffer in pieces gives the same result as encrypting it in one call.
+// NOT cryptographically secure.
+func NewStream(seed uint64) cipher.Stream {
+	return New(seed)
+}
+
+// XORKeyStream XORs each byte of src with the next byte of the stream and
+// writes the result to dst
+// dst and src may overlap entirely; len(dst) must be at least len(src).
+// XORing the output again with an identically seeded RNG recovers src.
+// NOT cryptographically secure.
+func (r *RNG) XORKeyStream(dst, src []byte) {
+	if len(dst) < len(src) {
+		panic("rand: output smaller than input")
+	}
+
+	var key [256]byte
+	for len(src) > 0 {
+		n := len(src)
+		if n > len(key) {
+			n = len(key)
+		}
+		r.Read(key[:n])
+		for i, k := range key[:n] {
+			dst[i] = src[i] ^ k
+		}
+		dst, src = dst[n:], src[n:]
+	}
+}
@@ -0,0 +1,45 @@
+package rand
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestXORKeyStreamRoundTrip(t *testing.T) {
+	plain := make([]byte, 5000)
+	for i := range plain {
+		plain[i] = byte(i * 7)
+	}
+
+	cipherText := make([]byte, len(plain))
+	New(424242).XORKeyStream(cipherText, plain)
+	if bytes.Equal(cipherText, plain) {
+		t.Fatal("keystream left the data unchanged")
+	}
+
+	// Decrypt in place
+	New(424242).XORKeyStream(cipherText, cipherText)
+	if !bytes.Equal(cipherText, plain) {
+		t.Error("decryption did not recover the plaintext")
+	}
+}
+
+func TestStreamChunkedMatchesWhole(t *testing.T) {
+	plain := bytes.Repeat([]byte("attack at dawn! "), 64)
+
+	whole := make([]byte, len(plain))
+	NewStream(9).XORKeyStream(whole, plain)
+
+	chunked := make([]byte, len(plain))
+	s := NewStream(9)
+	s.XORKeyStream(chunked[:16], plain[:16])
+	s.XORKeyStream(chunked[16:32], plain[16:32])
+	for off := 32; off < len(plain); off += 37 {
+		end := min(off+37, len(plain))
+		s.XORKeyStream(chunked[off:end], plain[off:end])
+	}
+
+	if !bytes.Equal(chunked, whole) {
+		t.Error("chunked encryption differs from whole-buffer encryption")
+	}
+}
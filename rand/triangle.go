@@ -0,0 +1,48 @@
+package rand
+
+// This file contains helpers for looking at the automaton itself rather than
+// its output.
+
+// GenerateTriangle returns the first width cells of the strip seeded with
+// seed over successive generations, one row per generation
+// Row 0 is the seeded strip and each later row is one step of the previous
+// one, so the rows are true consecutive generations, as drawn by the r30r2
+// visualizer. It panics unless 1 <= width <= DefaultWidth and generations is
+// non-negative.
+func GenerateTriangle(seed uint64, generations, width int) [][]bool {
+	if width < 1 || width > DefaultWidth || generations < 0 {
+		panic("invalid argument to GenerateTriangle")
+	}
+
+	r := New(seed)
+	grid := make([][]bool, generations)
+	for gen := range grid {
+		if gen > 0 {
+			r.step()
+		}
+		row := make([]bool, width)
+		for c := range row {
+			row[c] = r.state[c/64]>>(63-c%64)&1 == 1
+		}
+		grid[gen] = row
+	}
+	return grid
+}
+
+// NewImpulse creates an RNG on a strip of width cells with only the center
+// cell set, the starting row of the textbook Rule 30 pictures
+// No seed pattern or warmup is applied, so the first generations grow as a
+// triangle from cell width/2; output starts with the generation after the
+// impulse, as after SetState. Supported widths are those of NewWithWidth;
+// any other width panics.
+func NewImpulse(width int) *RNG {
+	rng, err := NewWithWidth(0, width)
+	if err != nil {
+		panic("invalid argument to NewImpulse")
+	}
+	state := make([]uint64, width/64)
+	c := width / 2
+	state[c/64] = 1 << (63 - c%64)
+	rng.SetState(state)
+	return rng
+}
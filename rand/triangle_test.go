@@ -0,0 +1,109 @@
+package rand
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateTriangle(t *testing.T) {
+	const width, gens = 12, 20
+	grid := GenerateTriangle(5, gens, width)
+	if len(grid) != gens {
+		t.Fatalf("got %d rows, want %d", len(grid), gens)
+	}
+
+	// Row 0 is the seeded strip
+	r := New(5)
+	for c, v := range grid[0] {
+		if v != cell(r, c) {
+			t.Fatalf("row 0 cell %d = %v, seeded strip has %v", c, v, cell(r, c))
+		}
+	}
+
+	// Cells whose whole neighbourhood is in view follow the rule
+	for gen := 1; gen < gens; gen++ {
+		prev, row := grid[gen-1], grid[gen]
+		if len(row) != width {
+			t.Fatalf("row %d has %d cells, want %d", gen, len(row), width)
+		}
+		for c := 2; c < width-2; c++ {
+			want := (prev[c-2] != prev[c-1]) != (prev[c] || prev[c+1] || prev[c+2])
+			if row[c] != want {
+				t.Fatalf("row %d cell %d = %v, rule gives %v", gen, c, row[c], want)
+			}
+		}
+	}
+
+	// At full width every row matches the reference evolution
+	strip := New(5).CopyState()
+	for gen, row := range GenerateTriangle(5, 10, DefaultWidth) {
+		if gen > 0 {
+			strip = referenceStep(strip)
+		}
+		for c, v := range row {
+			if v != (strip[c/64]>>(63-c%64)&1 == 1) {
+				t.Fatalf("full width row %d cell %d differs from the reference", gen, c)
+			}
+		}
+	}
+}
+
+func TestGenerateTrianglePanics(t *testing.T) {
+	for _, args := range [][2]int{{10, 0}, {10, DefaultWidth + 1}, {-1, 8}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("GenerateTriangle(1, %d, %d) did not panic", args[0], args[1])
+				}
+			}()
+			GenerateTriangle(1, args[0], args[1])
+		}()
+	}
+}
+
+func TestImpulseTriangle(t *testing.T) {
+	// The radius-2 rule from a single cell, worked out by hand: each
+	// generation grows by two cells on either side
+	triangle := []string{
+		"1",
+		"11111",
+		"111011101",
+		"1110110011011",
+		"11101100101010001",
+		"111011001010000101111",
+		"1110110010100111110001101",
+	}
+
+	for _, width := range []int{128, 256, 512, 1024} {
+		r := NewImpulse(width)
+		center := width / 2
+		for gen, row := range triangle {
+			var sb strings.Builder
+			for c := 0; c < width; c++ {
+				if cell(r, c) {
+					if c < center-2*gen || c > center+2*gen {
+						t.Fatalf("width %d generation %d: cell %d set outside the triangle", width, gen, c)
+					}
+				}
+			}
+			for c := center - 2*gen; c <= center+2*gen; c++ {
+				if cell(r, c) {
+					sb.WriteByte('1')
+				} else {
+					sb.WriteByte('0')
+				}
+			}
+			if sb.String() != row {
+				t.Fatalf("width %d generation %d: got %s, want %s", width, gen, sb.String(), row)
+			}
+			r.step()
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("NewImpulse(100) did not panic")
+		}
+	}()
+	NewImpulse(100)
+}
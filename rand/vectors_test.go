@@ -0,0 +1,68 @@
+package rand
+
+import (
+	_ "embed"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Regenerate with: go test ./rand -run TestKnownVectors -update
+var update = flag.Bool("update", false, "rewrite testdata/vectors.txt")
+
+//go:embed testdata/vectors.txt
+var vectorFile string
+
+var vectorSeeds = []uint64{0, 1, 2, 42, 12345, 0xdeadbeef, 1 << 63, math.MaxUint64}
+
+const vectorBytes = 64
+
+func TestKnownVectors(t *testing.T) {
+	if *update {
+		vectors := GenerateVectors(vectorSeeds, vectorBytes)
+		var b strings.Builder
+		b.WriteString("# seed (decimal) and the first 64 bytes of New(seed), in hex\n")
+		for _, seed := range vectorSeeds {
+			fmt.Fprintf(&b, "%d %x\n", seed, vectors[seed])
+		}
+		if err := os.WriteFile("testdata/vectors.txt", []byte(b.String()), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		t.Skip("vectors rewritten")
+	}
+
+	var seeds []uint64
+	for line := range strings.Lines(vectorFile) {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			t.Fatalf("malformed vector line %q", line)
+		}
+		seed, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := hex.DecodeString(fields[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := GenerateVectors([]uint64{seed}, len(want))[seed]; !slices.Equal(got, want) {
+			t.Errorf("seed %d: output changed\n got %x\nwant %x", seed, got, want)
+		}
+		seeds = append(seeds, seed)
+	}
+
+	for _, seed := range []uint64{0, 1, math.MaxUint64} {
+		if !slices.Contains(seeds, seed) {
+			t.Errorf("no vector for seed %d", seed)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package stats
+
+// This file contains streaming forms of the byte-frequency measures, for
+// data too large to hold in memory.
+
+// EntropyAccumulator computes ShannonEntropy over data written to it in
+// pieces, keeping only the 256 byte counts
+type EntropyAccumulator struct {
+	counts [256]int
+	n      int
+}
+
+// NewEntropyAccumulator returns an empty EntropyAccumulator
+func NewEntropyAccumulator() *EntropyAccumulator {
+	return &EntropyAccumulator{}
+}
+
+// Write adds p to the data seen so far; it never fails
+func (a *EntropyAccumulator) Write(p []byte) (int, error) {
+	for _, b := range p {
+		a.counts[b]++
+	}
+	a.n += len(p)
+	return len(p), nil
+}
+
+// Entropy returns the Shannon entropy, in bits per byte, of everything
+// written so far
+// It equals ShannonEntropy of the concatenated writes.
+func (a *EntropyAccumulator) Entropy() float64 {
+	if a.n == 0 {
+		return 0
+	}
+	return countsEntropy(&a.counts, a.n)
+}
+
+// ChiSquareAccumulator computes ChiSquareUniform over data written to it in
+// pieces, keeping only the 256 byte counts
+type ChiSquareAccumulator struct {
+	counts [256]int
+	n      int
+}
+
+// NewChiSquareAccumulator returns an empty ChiSquareAccumulator
+func NewChiSquareAccumulator() *ChiSquareAccumulator {
+	return &ChiSquareAccumulator{}
+}
+
+// Write adds p to the data seen so far; it never fails
+func (a *ChiSquareAccumulator) Write(p []byte) (int, error) {
+	for _, b := range p {
+		a.counts[b]++
+	}
+	a.n += len(p)
+	return len(p), nil
+}
+
+// ChiSquare returns the chi-square statistic of everything written so far
+// against a uniform distribution
+// It equals ChiSquareUniform of the concatenated writes.
+func (a *ChiSquareAccumulator) ChiSquare() float64 {
+	if a.n == 0 {
+		return 0
+	}
+	return countsChiSquare(&a.counts, a.n)
+}
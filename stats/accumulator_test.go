@@ -0,0 +1,37 @@
+package stats
+
+import "testing"
+
+func TestEntropyAccumulatorMatchesBatch(t *testing.T) {
+	data := generated(100000)
+	data = append(data, make([]byte, 5000)...) // skew the distribution a little
+
+	acc := NewEntropyAccumulator()
+	for off := 0; off < len(data); off += 777 {
+		acc.Write(data[off:min(off+777, len(data))])
+	}
+	if got, want := acc.Entropy(), ShannonEntropy(data); got != want {
+		t.Errorf("chunked entropy = %v, batch = %v", got, want)
+	}
+
+	if e := NewEntropyAccumulator().Entropy(); e != 0 {
+		t.Errorf("empty accumulator: entropy = %v, want 0", e)
+	}
+}
+
+func TestChiSquareAccumulatorMatchesBatch(t *testing.T) {
+	data := generated(100000)
+	data = append(data, make([]byte, 5000)...)
+
+	acc := NewChiSquareAccumulator()
+	for off := 0; off < len(data); off += 1000 {
+		acc.Write(data[off:min(off+1000, len(data))])
+	}
+	if got, want := acc.ChiSquare(), ChiSquareUniform(data); got != want {
+		t.Errorf("chunked chi-square = %v, batch = %v", got, want)
+	}
+
+	if chi := NewChiSquareAccumulator().ChiSquare(); chi != 0 {
+		t.Errorf("empty accumulator: chi-square = %v, want 0", chi)
+	}
+}
@@ -0,0 +1,65 @@
+package stats
+
+import (
+	"math"
+	"math/bits"
+)
+
+// This file contains tests from NIST SP 800-22, "A Statistical Test Suite
+// for Random and Pseudorandom Number Generators". Each returns a p-value and
+// whether it clears the suite's conventional 0.01 significance level.
+
+// Significance is the p-value below which a NIST test is considered failed
+const Significance = 0.01
+
+// MonobitTest runs the NIST frequency (monobit) test over every bit of data
+// It checks that ones and zeros are about equally common.
+func MonobitTest(data []byte) (p float64, passed bool) {
+	n := len(data) * 8
+	if n == 0 {
+		return 0, false
+	}
+
+	ones := 0
+	for _, b := range data {
+		ones += bits.OnesCount8(b)
+	}
+	sum := float64(2*ones - n) // +1 per one, -1 per zero
+	sObs := math.Abs(sum) / math.Sqrt(float64(n))
+	p = math.Erfc(sObs / math.Sqrt2)
+	return p, p >= Significance
+}
+
+// RunsTest runs the NIST runs test over every bit of data, least significant
+// bit of each byte first
+// It checks that the stream switches between zeros and ones as often as a
+// random one would, catching data that is balanced but too regular (or too
+// sticky). As in NIST, data that fails the monobit prerequisite fails here
+// with p = 0.
+func RunsTest(data []byte) (p float64, passed bool) {
+	n := len(data) * 8
+	if n == 0 {
+		return 0, false
+	}
+
+	ones := 0
+	transitions := 0
+	prev := data[0] & 1
+	for _, b := range data {
+		ones += bits.OnesCount8(b)
+		transitions += int(prev ^ (b & 1))                    // across the byte boundary
+		transitions += bits.OnesCount8((b ^ (b >> 1)) & 0x7F) // within the byte
+		prev = b >> 7
+	}
+
+	fn := float64(n)
+	pi := float64(ones) / fn
+	if math.Abs(pi-0.5) >= 2/math.Sqrt(fn) {
+		return 0, false
+	}
+
+	vObs := float64(transitions + 1)
+	q := pi * (1 - pi)
+	p = math.Erfc(math.Abs(vObs-2*fn*q) / (2 * math.Sqrt(2*fn) * q))
+	return p, p >= Significance
+}
@@ -0,0 +1,44 @@
+package stats
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/vrypan/r30r2/rand"
+)
+
+// generated returns n bytes of R30R2 output for a fixed seed
+func generated(n int) []byte {
+	data := make([]byte, n)
+	rand.New(12345).Read(data)
+	return data
+}
+
+func TestMonobit(t *testing.T) {
+	if p, passed := MonobitTest(make([]byte, 1024)); passed {
+		t.Errorf("all-zero data passed (p = %g)", p)
+	}
+	if p, passed := MonobitTest(generated(1 << 20)); !passed {
+		t.Errorf("generator output failed (p = %g)", p)
+	}
+}
+
+func TestRuns(t *testing.T) {
+	alternating := bytes.Repeat([]byte{0x55}, 1024) // 0101...
+	if p, passed := RunsTest(alternating); passed {
+		t.Errorf("alternating bits passed (p = %g)", p)
+	}
+	if p, passed := RunsTest(generated(1 << 20)); !passed {
+		t.Errorf("generator output failed (p = %g)", p)
+	}
+}
+
+func TestRunsKnownValue(t *testing.T) {
+	// 0x59 is 1,0,0,1,1,0,1,0 least significant bit first: four ones and
+	// six runs, so p = erfc(|6-4| / (2*sqrt(16)*0.25)) = erfc(1).
+	p, _ := RunsTest([]byte{0x59})
+	if math.Abs(p-math.Erfc(1)) > 1e-12 {
+		t.Errorf("p = %v, want %v", p, math.Erfc(1))
+	}
+}
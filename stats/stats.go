@@ -0,0 +1,160 @@
+// Package stats provides simple statistical measures for assessing the
+// quality of random byte streams.
+package stats
+
+import (
+	"math"
+	"math/bits"
+)
+
+// byteCounts returns how many times each byte value occurs in data
+func byteCounts(data []byte) [256]int {
+	var counts [256]int
+	for _, b := range data {
+		counts[b]++
+	}
+	return counts
+}
+
+// ShannonEntropy returns the Shannon entropy of data in bits per byte
+// Ranges from 0 (a single repeated value) to 8 (all values equally frequent).
+func ShannonEntropy(data []byte) float64 {
+	if len(data) == 0 {
+		return 0
+	}
+
+	counts := byteCounts(data)
+	return countsEntropy(&counts, len(data))
+}
+
+// countsEntropy returns the Shannon entropy of n bytes with the given counts
+func countsEntropy(counts *[256]int, n int) float64 {
+	total := float64(n)
+	entropy := 0.0
+	for _, c := range counts {
+		if c > 0 {
+			p := float64(c) / total
+			entropy -= p * math.Log2(p)
+		}
+	}
+	return entropy
+}
+
+// ChiSquareUniform returns the chi-square statistic of the byte frequencies
+// in data against a uniform distribution (255 degrees of freedom)
+// Random data lands near 255; values above ~310 or below ~200 are suspicious.
+func ChiSquareUniform(data []byte) float64 {
+	if len(data) == 0 {
+		return 0
+	}
+
+	counts := byteCounts(data)
+	return countsChiSquare(&counts, len(data))
+}
+
+// countsChiSquare returns the chi-square statistic of n bytes with the given
+// counts against a uniform distribution
+func countsChiSquare(counts *[256]int, n int) float64 {
+	expected := float64(n) / 256
+	chi := 0.0
+	for _, c := range counts {
+		d := float64(c) - expected
+		chi += d * d / expected
+	}
+	return chi
+}
+
+// SerialCorrelation returns the lag-1 serial correlation coefficient of the
+// bytes in data, computed the same way as the ent tool (the last byte is
+// paired with the first)
+// Random data lands near 0; values approach 1 when each byte predicts the
+// next. It is NaN when data has fewer than two bytes or no variation.
+func SerialCorrelation(data []byte) float64 {
+	if len(data) < 2 {
+		return math.NaN()
+	}
+
+	var sum, sumSq, sumProd float64
+	prev := float64(data[len(data)-1])
+	for _, b := range data {
+		x := float64(b)
+		sum += x
+		sumSq += x * x
+		sumProd += prev * x
+		prev = x
+	}
+	n := float64(len(data))
+	den := n*sumSq - sum*sum
+	if den == 0 {
+		return math.NaN()
+	}
+	return (n*sumProd - sum*sum) / den
+}
+
+// AdjacentBitCorrelation returns the correlation coefficient between each
+// bit of data and the next, least significant bit of each byte first
+// Like SerialCorrelation it wraps around, pairing the last bit with the
+// first. Random data lands near 0; values approach 1 when neighbouring bits
+// tend to be equal and -1 when they tend to differ. It is NaN when data is
+// empty or all its bits are the same.
+func AdjacentBitCorrelation(data []byte) float64 {
+	if len(data) == 0 {
+		return math.NaN()
+	}
+
+	ones, both := 0, 0
+	prev := data[len(data)-1] >> 7
+	for _, b := range data {
+		ones += bits.OnesCount8(b)
+		both += int(prev & b & 1)                    // across the byte boundary
+		both += bits.OnesCount8(b & (b >> 1) & 0x7F) // within the byte
+		prev = b >> 7
+	}
+
+	n := float64(len(data) * 8)
+	s := float64(ones)
+	den := n*s - s*s
+	if den == 0 {
+		return math.NaN()
+	}
+	return (n*float64(both) - s*s) / den
+}
+
+// MinEntropy returns the min-entropy of data in bits per byte, -log2 of the
+// frequency of the most common byte value
+// It never exceeds ShannonEntropy and reflects how well an attacker could do
+// by always guessing the likeliest byte.
+func MinEntropy(data []byte) float64 {
+	if len(data) == 0 {
+		return 0
+	}
+
+	counts := byteCounts(data)
+	most := 0
+	for _, c := range counts {
+		most = max(most, c)
+	}
+	return -math.Log2(float64(most) / float64(len(data)))
+}
+
+// MonteCarloPi estimates Pi from data the way the ent tool does
+// Each 6 bytes form a point of two 24-bit big-endian coordinates; the
+// fraction of points inside the quarter circle of radius 2^24-1 approaches
+// Pi/4. Trailing bytes that do not fill a point are ignored, and data
+// shorter than 6 bytes yields 0.
+func MonteCarloPi(data []byte) float64 {
+	const r = 1<<24 - 1
+	points, inside := 0, 0
+	for i := 0; i+6 <= len(data); i += 6 {
+		x := float64(int(data[i])<<16 | int(data[i+1])<<8 | int(data[i+2]))
+		y := float64(int(data[i+3])<<16 | int(data[i+4])<<8 | int(data[i+5]))
+		if x*x+y*y <= r*r {
+			inside++
+		}
+		points++
+	}
+	if points == 0 {
+		return 0
+	}
+	return 4 * float64(inside) / float64(points)
+}
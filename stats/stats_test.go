@@ -0,0 +1,121 @@
+package stats
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/vrypan/r30r2/rand"
+)
+
+func TestShannonEntropy(t *testing.T) {
+	if e := ShannonEntropy(bytes.Repeat([]byte{0x42}, 1000)); e != 0 {
+		t.Errorf("constant data: entropy = %v, want 0", e)
+	}
+
+	perm := make([]byte, 256)
+	for i := range perm {
+		perm[i] = byte(i * 167) // 167 is odd, so this is a permutation
+	}
+	if e := ShannonEntropy(perm); math.Abs(e-8) > 1e-12 {
+		t.Errorf("uniform data: entropy = %v, want 8", e)
+	}
+
+	if e := ShannonEntropy(nil); e != 0 {
+		t.Errorf("empty data: entropy = %v, want 0", e)
+	}
+}
+
+func TestChiSquareUniform(t *testing.T) {
+	if chi := ChiSquareUniform(bytes.Repeat([]byte{7}, 1<<16)); chi < 1e6 {
+		t.Errorf("constant data: chi-square = %.1f, want a huge value", chi)
+	}
+
+	data := make([]byte, 1<<20)
+	rand.New(12345).Read(data)
+	if chi := ChiSquareUniform(data); chi < 200 || chi > 310 {
+		t.Errorf("generator output: chi-square = %.1f, want about 255", chi)
+	}
+}
+
+func TestSerialCorrelation(t *testing.T) {
+	ramp := make([]byte, 256)
+	for i := range ramp {
+		ramp[i] = byte(i)
+	}
+	if c := SerialCorrelation(ramp); c < 0.9 {
+		t.Errorf("ramp: correlation = %.4f, want close to 1", c)
+	}
+
+	data := make([]byte, 1<<20)
+	rand.New(12345).Read(data)
+	if c := SerialCorrelation(data); math.Abs(c) > 0.005 {
+		t.Errorf("generator output: correlation = %.5f, want about 0", c)
+	}
+
+	if c := SerialCorrelation(bytes.Repeat([]byte{9}, 100)); !math.IsNaN(c) {
+		t.Errorf("constant data: correlation = %v, want NaN", c)
+	}
+}
+
+func TestAdjacentBitCorrelation(t *testing.T) {
+	// Every bit of the random source is emitted twice in a row
+	src := generated(1 << 16)
+	doubled := make([]byte, 2*len(src))
+	for i, b := range src {
+		for j := range 8 {
+			bit := b >> j & 1
+			doubled[2*i+j/4] |= (bit | bit<<1) << (2 * (j % 4))
+		}
+	}
+	if c := AdjacentBitCorrelation(doubled); c < 0.4 {
+		t.Errorf("doubled bits: correlation = %.4f, want about 0.5", c)
+	}
+
+	if c := AdjacentBitCorrelation(bytes.Repeat([]byte{0x55}, 100)); math.Abs(c+1) > 1e-12 {
+		t.Errorf("alternating bits: correlation = %v, want -1", c)
+	}
+
+	if c := AdjacentBitCorrelation(generated(1 << 20)); math.Abs(c) > 0.002 {
+		t.Errorf("generator output: correlation = %.5f, want about 0", c)
+	}
+
+	if c := AdjacentBitCorrelation(make([]byte, 10)); !math.IsNaN(c) {
+		t.Errorf("all-zero data: correlation = %v, want NaN", c)
+	}
+}
+
+func TestMinEntropy(t *testing.T) {
+	skewed := make([]byte, 1000) // 901 zeros, 99 distinct values
+	for i := 1; i < 100; i++ {
+		skewed[i] = byte(i)
+	}
+	if e, want := MinEntropy(skewed), -math.Log2(0.901); math.Abs(e-want) > 1e-12 {
+		t.Errorf("skewed data: min-entropy = %v, want %v", e, want)
+	}
+	if MinEntropy(skewed) >= ShannonEntropy(skewed) {
+		t.Error("min-entropy should be below Shannon entropy for skewed data")
+	}
+
+	data := make([]byte, 1<<20)
+	rand.New(12345).Read(data)
+	if e := MinEntropy(data); e < 7.9 || e > 8 {
+		t.Errorf("generator output: min-entropy = %v, want close to 8", e)
+	}
+}
+
+func TestMonteCarloPi(t *testing.T) {
+	data := make([]byte, 4<<20)
+	rand.New(12345).Read(data)
+	if pi := MonteCarloPi(data); math.Abs(pi-math.Pi)/math.Pi > 0.01 {
+		t.Errorf("generator output: Pi = %f, want within 1%% of %f", pi, math.Pi)
+	}
+
+	// Every point sits at the origin, inside the circle
+	if pi := MonteCarloPi(make([]byte, 600)); pi != 4 {
+		t.Errorf("all-zero data: Pi = %v, want 4", pi)
+	}
+	if pi := MonteCarloPi(make([]byte, 5)); pi != 0 {
+		t.Errorf("no complete point: Pi = %v, want 0", pi)
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"os"
+
+	"github.com/vrypan/r30r2/rand"
+)
+
+func main() {
+	bytes := flag.Int64("bytes", 1024*1024*1024, "Number of bytes to generate (0 = unlimited)")
+	seed := flag.Uint64("seed", 12345, "Random seed")
+	flag.Parse()
+
+	if err := generate(os.Stdout, *seed, *bytes); err != nil {
+		if *bytes == 0 {
+			// Unlimited mode ends when the pipe closes
+			os.Exit(0)
+		}
+		os.Exit(1)
+	}
+}
+
+// generate writes n bytes of the R30R2 stream for seed to w, or streams
+// forever when n is 0, returning only once w fails
+func generate(w io.Writer, seed uint64, n int64) error {
+	rng := rand.New(seed)
+	if n == 0 {
+		_, err := rng.WriteTo(w)
+		return err
+	}
+	_, err := io.CopyN(w, rng, n)
+	return err
+}
@@ -0,0 +1,29 @@
+package main
+
+// Run with: go test r30-gen.go r30-gen_test.go
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/vrypan/r30r2/rand"
+)
+
+func TestGenerateDeterministic(t *testing.T) {
+	var a, b bytes.Buffer
+	if err := generate(&a, 7, 10000); err != nil {
+		t.Fatal(err)
+	}
+	if err := generate(&b, 7, 10000); err != nil {
+		t.Fatal(err)
+	}
+	if a.Len() != 10000 || !bytes.Equal(a.Bytes(), b.Bytes()) {
+		t.Fatalf("two runs gave %d and %d differing bytes", a.Len(), b.Len())
+	}
+
+	want := make([]byte, 10000)
+	rand.New(7).Read(want)
+	if !bytes.Equal(a.Bytes(), want) {
+		t.Error("output differs from rand.New(seed).Read")
+	}
+}